@@ -0,0 +1,144 @@
+package calc
+
+import "math"
+
+// Gradient computes field components X, Y, Z and the spatial gradient tensor of the field
+// from spherical harmonic (sh) coeffs `gh` at geodetic latitude `flat`, longitude `flon` (decimal degrees)
+// and altitude `elev` (km above mean sea level).
+//
+// Both are in the local geodetic frame: north, east, down. The tensor element [i][j] is
+// the derivative of the i-th component along the j-th axis in nT/km. As the field is a gradient of the potential
+// and the potential is harmonic, the tensor is symmetric and its trace is zero.
+func Gradient(flat, flon, elev float64, nmax int, gh *[]float64) ([3]float64, [3][3]float64) {
+	slat, clat, r, cd, sd := geodeticToGeocentric(flat, elev)
+	b, grad := gradientSpherical(slat, clat, r, flon, nmax, gh)
+	// rotation from geocentric to geodetic frame, see Shval3
+	rotation := [3][3]float64{{cd, 0, sd}, {0, 1, 0}, {-sd, 0, cd}}
+	var b_geodetic [3]float64
+	var grad_geodetic [3][3]float64
+	for i := 0; i < 3; i++ {
+		for k := 0; k < 3; k++ {
+			b_geodetic[i] += rotation[i][k] * b[k]
+			for j := 0; j < 3; j++ {
+				for l := 0; l < 3; l++ {
+					grad_geodetic[i][j] += rotation[i][k] * grad[k][l] * rotation[j][l]
+				}
+			}
+		}
+	}
+	return b_geodetic, grad_geodetic
+}
+
+// Computes field components and the gradient tensor in the local spherical frame converted into
+// north (-theta), east (phi), down (-r) for geocentric sine and cosine of the latitude and radius `r` (km).
+func gradientSpherical(slat, clat, r, flon float64, nmax int, gh *[]float64) ([3]float64, [3][3]float64) {
+	var earths_radius float64 = 6371.2
+	var dtr float64 = 0.01745329
+	// colatitude theta: cos(theta) = slat, sin(theta) = clat
+	ct, st := slat, clat
+	p, dp, d2p := schmidtLegendre(ct, st, nmax)
+	// field components and derivatives in the spherical frame
+	var br, bt, bp float64
+	var dbr_dr, dbr_dt, dbr_dp, dbt_dr, dbt_dt, dbt_dp, dbp_dr, dbp_dt, dbp_dp float64
+	l := 0
+	for n := 1; n <= nmax; n++ {
+		fn := float64(n)
+		rr := math.Pow(earths_radius/r, fn+2)
+		for m := 0; m <= n; m++ {
+			fm := float64(m)
+			var g, h float64
+			g = (*gh)[l]
+			if m == 0 {
+				l++
+			} else {
+				h = (*gh)[l+1]
+				l += 2
+			}
+			sm, cm := math.Sincos(fm * flon * dtr)
+			// the longitude part and its derivative along longitude
+			s := g*cm + h*sm
+			s1 := fm * (h*cm - g*sm)
+			k := legendreIndex(n, m)
+			br += (fn + 1) * rr * s * p[k]
+			bt -= rr * s * dp[k]
+			bp -= rr * s1 * p[k] / st
+
+			dbr_dr -= (fn + 1) * (fn + 2) / r * rr * s * p[k]
+			dbr_dt += (fn + 1) * rr * s * dp[k]
+			dbr_dp += (fn + 1) * rr * s1 * p[k]
+			dbt_dr += (fn + 2) / r * rr * s * dp[k]
+			dbt_dt -= rr * s * d2p[k]
+			dbt_dp -= rr * s1 * dp[k]
+			dbp_dr += (fn + 2) / r * rr * s1 * p[k] / st
+			dbp_dt -= rr * s1 * (dp[k]*st - p[k]*ct) / (st * st)
+			dbp_dp += rr * fm * fm * s * p[k] / st
+		}
+	}
+	// the gradient of a vector field in the local spherical frame (r, theta, phi)
+	cot := ct / st
+	spherical := [3][3]float64{
+		{dbr_dr, dbr_dt/r - bt/r, dbr_dp/(r*st) - bp/r},
+		{dbt_dr, dbt_dt/r + br/r, dbt_dp/(r*st) - bp*cot/r},
+		{dbp_dr, dbp_dt / r, dbp_dp/(r*st) + br/r + bt*cot/r},
+	}
+	// north = -theta, east = phi, down = -r
+	axes := [3]int{1, 2, 0}
+	signs := [3]float64{-1, 1, -1}
+	b := [3]float64{-bt, bp, -br}
+	var grad [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			grad[i][j] = signs[i] * signs[j] * spherical[axes[i]][axes[j]]
+		}
+	}
+	return b, grad
+}
+
+// Returns the position of the Legendre function of degree `n` and order `m`.
+func legendreIndex(n, m int) int {
+	return n*(n+1)/2 + m
+}
+
+// Computes Schmidt semi-normalised associated Legendre functions for cos(theta) = `ct`, sin(theta) = `st`
+// and their first and second derivatives with respect to theta, all up to degree `nmax`.
+func schmidtLegendre(ct, st float64, nmax int) ([]float64, []float64, []float64) {
+	size := legendreIndex(nmax, nmax) + 1
+	p := make([]float64, size)
+	dp := make([]float64, size)
+	d2p := make([]float64, size)
+	p[0] = 1
+	for n := 1; n <= nmax; n++ {
+		fn := float64(n)
+		// sectoral functions
+		k := legendreIndex(n, n)
+		if n == 1 {
+			p[k], dp[k], d2p[k] = st, ct, -st
+		} else {
+			prev := legendreIndex(n-1, n-1)
+			c := math.Sqrt(1 - 1/(2*fn))
+			p[k] = c * st * p[prev]
+			dp[k] = c * (ct*p[prev] + st*dp[prev])
+			d2p[k] = c * (-st*p[prev] + 2*ct*dp[prev] + st*d2p[prev])
+		}
+		for m := 0; m < n; m++ {
+			fm := float64(m)
+			k := legendreIndex(n, m)
+			k1 := legendreIndex(n-1, m)
+			norm := math.Sqrt(fn*fn - fm*fm)
+			p[k] = (2*fn - 1) * ct * p[k1]
+			dp[k] = (2*fn - 1) * (ct*dp[k1] - st*p[k1])
+			d2p[k] = (2*fn - 1) * (ct*d2p[k1] - 2*st*dp[k1] - ct*p[k1])
+			if n-2 >= m {
+				k2 := legendreIndex(n-2, m)
+				c := math.Sqrt((fn-1)*(fn-1) - fm*fm)
+				p[k] -= c * p[k2]
+				dp[k] -= c * dp[k2]
+				d2p[k] -= c * d2p[k2]
+			}
+			p[k] /= norm
+			dp[k] /= norm
+			d2p[k] /= norm
+		}
+	}
+	return p, dp, d2p
+}
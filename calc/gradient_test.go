@@ -0,0 +1,113 @@
+package calc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/proway2/go-igrf/coeffs"
+)
+
+func TestGradient(t *testing.T) {
+	shc, _ := coeffs.NewCoeffsData()
+	gha, ghb, nmax, _ := shc.Coeffs(2020.5)
+	tests := []struct {
+		name string
+		lat  float64
+		lon  float64
+		alt  float64
+	}{
+		{name: "Equator", lat: 0.0, lon: 0.0, alt: 0.0},
+		{name: "Mid latitude", lat: 59.9, lon: 39.9, alt: 0.0},
+		{name: "Southern hemisphere", lat: -45.5, lon: -120.3, alt: 300.0},
+		{name: "Near North pole", lat: 89.5, lon: 170.0, alt: 10.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, grad := Gradient(tt.lat, tt.lon, tt.alt, nmax, gha)
+			x, y, z, _, _, _ := Shval3(tt.lat, tt.lon, tt.alt, nmax, gha, ghb)
+			want := [3]float64{x, y, z}
+			for i := range want {
+				if math.Abs(b[i]-want[i]) > 1e-6 {
+					t.Errorf("Gradient() component %v = %v, want %v", i, b[i], want[i])
+				}
+			}
+			var trace, scale float64
+			for i := 0; i < 3; i++ {
+				trace += grad[i][i]
+				for j := 0; j < 3; j++ {
+					scale = math.Max(scale, math.Abs(grad[i][j]))
+				}
+			}
+			if math.Abs(trace) > 1e-9*scale {
+				t.Errorf("Gradient() trace = %v, want 0", trace)
+			}
+			for i := 0; i < 3; i++ {
+				for j := i + 1; j < 3; j++ {
+					if math.Abs(grad[i][j]-grad[j][i]) > 1e-9*scale {
+						t.Errorf("Gradient() [%v][%v] = %v, [%v][%v] = %v, want symmetric", i, j, grad[i][j], j, i, grad[j][i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGradientSphericalFiniteDifference(t *testing.T) {
+	// the tensor is compared with central differences of the field vector taken in Cartesian coordinates
+	shc, _ := coeffs.NewCoeffsData()
+	gha, _, nmax, _ := shc.Coeffs(2020.5)
+	var dtr float64 = 0.01745329
+	lat, lon, r := 52.3*dtr, 21.7*dtr, 6571.2
+	slat, clat := math.Sincos(lat)
+	_, grad := gradientSpherical(slat, clat, r, lon/dtr, nmax, gha)
+	axes := nedAxes(lat, lon)
+	position := [3]float64{r * clat * math.Cos(lon), r * clat * math.Sin(lon), r * slat}
+	step := 0.01
+	for j := 0; j < 3; j++ {
+		var plus, minus [3]float64
+		for k := 0; k < 3; k++ {
+			plus[k] = position[k] + step*axes[j][k]
+			minus[k] = position[k] - step*axes[j][k]
+		}
+		b_plus := cartesianField(plus, nmax, gha)
+		b_minus := cartesianField(minus, nmax, gha)
+		for i := 0; i < 3; i++ {
+			var want float64
+			for k := 0; k < 3; k++ {
+				want += axes[i][k] * (b_plus[k] - b_minus[k]) / (2 * step)
+			}
+			if math.Abs(grad[i][j]-want) > 1e-4 {
+				t.Errorf("gradientSpherical() [%v][%v] = %v, want %v", i, j, grad[i][j], want)
+			}
+		}
+	}
+}
+
+// Returns unit vectors north, east, down at geocentric latitude `lat` and longitude `lon` (radians) in Cartesian coordinates.
+func nedAxes(lat, lon float64) [3][3]float64 {
+	slat, clat := math.Sincos(lat)
+	slon, clon := math.Sincos(lon)
+	return [3][3]float64{
+		{-slat * clon, -slat * slon, clat},
+		{-slon, clon, 0},
+		{-clat * clon, -clat * slon, -slat},
+	}
+}
+
+// Returns the field vector in Cartesian coordinates at `position` (km).
+func cartesianField(position [3]float64, nmax int, gh *[]float64) [3]float64 {
+	var dtr float64 = 0.01745329
+	r := math.Sqrt(position[0]*position[0] + position[1]*position[1] + position[2]*position[2])
+	lat := math.Asin(position[2] / r)
+	lon := math.Atan2(position[1], position[0])
+	slat, clat := math.Sincos(lat)
+	b, _ := gradientSpherical(slat, clat, r, lon/dtr, nmax, gh)
+	axes := nedAxes(lat, lon)
+	var field [3]float64
+	for i := 0; i < 3; i++ {
+		for k := 0; k < 3; k++ {
+			field[k] += b[i] * axes[i][k]
+		}
+	}
+	return field
+}
@@ -0,0 +1,53 @@
+package calc
+
+import "math"
+
+// Potential computes the magnetic scalar potential V (nT·km) and field components X, Y, Z as its negative gradient
+// from spherical harmonic (sh) coeffs `gh` at geodetic latitude `flat`, longitude `flon` (decimal degrees)
+// and altitude `elev` (km above mean sea level). Components are in the local geodetic frame, the same as `Shval3`.
+func Potential(flat, flon, elev float64, nmax int, gh *[]float64) (float64, float64, float64, float64) {
+	slat, clat, r, cd, sd := geodeticToGeocentric(flat, elev)
+	v, b := potentialSpherical(slat, clat, r, flon, nmax, gh)
+	// rotation from geocentric to geodetic frame, see Shval3
+	x := b[0]*cd + b[2]*sd
+	z := b[2]*cd - b[0]*sd
+	return v, x, b[1], z
+}
+
+// Computes the potential and field components north (-theta), east (phi), down (-r)
+// for geocentric sine and cosine of the latitude and radius `r` (km).
+func potentialSpherical(slat, clat, r, flon float64, nmax int, gh *[]float64) (float64, [3]float64) {
+	var earths_radius float64 = 6371.2
+	var dtr float64 = 0.01745329
+	// colatitude theta: cos(theta) = slat, sin(theta) = clat
+	ct, st := slat, clat
+	p, dp, _ := schmidtLegendre(ct, st, nmax)
+	var v, br, bt, bp float64
+	l := 0
+	for n := 1; n <= nmax; n++ {
+		fn := float64(n)
+		rr := math.Pow(earths_radius/r, fn+1)
+		for m := 0; m <= n; m++ {
+			fm := float64(m)
+			var g, h float64
+			g = (*gh)[l]
+			if m == 0 {
+				l++
+			} else {
+				h = (*gh)[l+1]
+				l += 2
+			}
+			sm, cm := math.Sincos(fm * flon * dtr)
+			s := g*cm + h*sm
+			s1 := fm * (h*cm - g*sm)
+			k := legendreIndex(n, m)
+			v += rr * s * p[k]
+			br += (fn + 1) * rr * s * p[k]
+			bt -= rr * s * dp[k]
+			bp -= rr * s1 * p[k] / st
+		}
+	}
+	// V = a * sum((a/r)^(n+1) * S * P), B = -grad(V)
+	ratio := earths_radius / r
+	return earths_radius * v, [3]float64{-bt * ratio, bp * ratio, -br * ratio}
+}
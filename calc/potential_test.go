@@ -0,0 +1,42 @@
+package calc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/proway2/go-igrf/coeffs"
+)
+
+func TestPotential(t *testing.T) {
+	shc, _ := coeffs.NewCoeffsData()
+	gha, ghb, nmax, _ := shc.Coeffs(2020.5)
+	tests := []struct {
+		name string
+		lat  float64
+		lon  float64
+		alt  float64
+	}{
+		{name: "Equator", lat: 0.0, lon: 0.0, alt: 0.0},
+		{name: "Mid latitude", lat: 59.9, lon: 39.9, alt: 0.0},
+		{name: "Southern hemisphere", lat: -45.5, lon: -120.3, alt: 300.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, x, y, z := Potential(tt.lat, tt.lon, tt.alt, nmax, gha)
+			want_x, want_y, want_z, _, _, _ := Shval3(tt.lat, tt.lon, tt.alt, nmax, gha, ghb)
+			if math.Abs(x-want_x) > 1e-6 || math.Abs(y-want_y) > 1e-6 || math.Abs(z-want_z) > 1e-6 {
+				t.Errorf("Potential() X, Y, Z = %v, %v, %v, want %v, %v, %v", x, y, z, want_x, want_y, want_z)
+			}
+			// Z = -dV/d(down), the central difference along altitude
+			step := 0.01
+			upper, _, _, _ := Potential(tt.lat, tt.lon, tt.alt+step, nmax, gha)
+			lower, _, _, _ := Potential(tt.lat, tt.lon, tt.alt-step, nmax, gha)
+			if want := (upper - lower) / (2 * step); math.Abs(z-want) > 1e-3 {
+				t.Errorf("Potential() Z = %v, want dV/dh = %v", z, want)
+			}
+			if v == 0 {
+				t.Errorf("Potential() V = %v, want non zero", v)
+			}
+		})
+	}
+}
@@ -11,10 +11,91 @@ import "math"
 // # Y - eastward component
 //
 // # Z - vertically-downward component
+//
+// `gha` and `ghb` must contain at least nmax*(nmax+2) coeffs, there is no upper limit for `nmax`.
 func Shval3(flat, flon, elev float64, nmax int, gha, ghb *[]float64) (float64, float64, float64, float64, float64, float64) {
+	return NewWorkspace(nmax).Shval3(flat, flon, elev, nmax, gha, ghb)
+}
+
+// Workspace holds buffers for the spherical harmonic sums, so that repeated computations don't allocate memory.
+//
+// Terms that depend on the latitude and altitude (Legendre functions, radial powers) are computed by `SetLatitude`,
+// terms that depend on the longitude are computed by `SetLongitude`, `Sum` computes field components using them.
+// This allows reusing terms along rows and columns of a grid.
+//
+// A workspace must not be used concurrently.
+type Workspace struct {
+	nmax   int
+	sl, cl []float64
+	p, q   []float64
+	// (earths_radius / r) ^ (n + 2) for every degree n
+	rr []float64
+	// geocentric sine and cosine of the latitude
+	slat, clat float64
+	// rotation from geocentric to geodetic components
+	cd, sd float64
+}
+
+// LongitudeTerms holds terms of the spherical harmonic sums that depend only on the longitude.
+type LongitudeTerms struct {
+	sl, cl []float64
+}
+
+// NewWorkspace returns a workspace for models with spherical harmonic degree up to `nmax`,
+// it grows automatically for higher degrees.
+func NewWorkspace(nmax int) *Workspace {
+	ws := &Workspace{}
+	ws.grow(nmax)
+	return ws
+}
+
+// Makes buffers large enough for the spherical harmonic degree `nmax`.
+func (ws *Workspace) grow(nmax int) {
+	if nmax <= ws.nmax && ws.sl != nil {
+		return
+	}
+	npq := (nmax * (nmax + 3)) / 2
+	// at least 4 Legendre functions are initialized in SetLatitude
+	legendre_size := npq + 1
+	if legendre_size < 5 {
+		legendre_size = 5
+	}
+	ws.nmax = nmax
+	ws.sl = make([]float64, trigSize(nmax))
+	ws.cl = make([]float64, trigSize(nmax))
+	ws.p = make([]float64, legendre_size)
+	ws.q = make([]float64, legendre_size)
+	ws.rr = make([]float64, nmax+1)
+}
+
+// Returns the number of longitude terms for the spherical harmonic degree `nmax`, sl[1] and cl[1] are always initialized.
+func trigSize(nmax int) int {
+	if nmax < 1 {
+		return 2
+	}
+	return nmax + 1
+}
+
+// Shval3 is the same as `Shval3`, but uses buffers of the workspace.
+func (ws *Workspace) Shval3(flat, flon, elev float64, nmax int, gha, ghb *[]float64) (float64, float64, float64, float64, float64, float64) {
+	ws.SetLatitude(flat, elev, nmax)
+	ws.SetLongitude(flon, nmax)
+	return ws.Sum(nmax, gha, ghb)
+}
+
+// SetLatitude computes terms of the spherical harmonic sums for geodetic latitude `flat` (decimal degrees)
+// and altitude `elev` (km above mean sea level).
+func (ws *Workspace) SetLatitude(flat, elev float64, nmax int) {
+	slat, clat, r, cd, sd := geodeticToGeocentric(flat, elev)
+	ws.setLegendre(slat, clat, r, nmax)
+	ws.cd, ws.sd = cd, sd
+}
+
+// Converts geodetic latitude `flat` (decimal degrees) and altitude `elev` (km above mean sea level)
+// into geocentric sine and cosine of the latitude, the radius (km), and returns cosine and sine
+// of the angle between geodetic and geocentric frames.
+func geodeticToGeocentric(flat, elev float64) (float64, float64, float64, float64, float64) {
 	// similar to shval3 from C implementation
-	var earths_radius float64 = 6371.2
-	var dtr float64 = 0.01745329
 	/*
 		a2,b2     - squares of semi-major and semi-minor axes of
 		the reference spheroid used for transforming
@@ -22,33 +103,8 @@ func Shval3(flat, flon, elev float64, nmax int, gha, ghb *[]float64) (float64, f
 	*/
 	var a2 float64 = 40680631.59 /* WGS84 */
 	var b2 float64 = 40408299.98 /* WGS84 */
-	var x, y, z, xtemp, ytemp, ztemp, aa, aa_temp, argument, clat, slat, sd, bb, cc, dd, r, ratio, power, rr, fn, fm float64
-	var l, n, m, npq int
-	var sl, cl [14]float64
-	var p, q [119]float64
-	argument = flat * dtr
-	slat = math.Sin(argument)
-	if (90.0 - flat) < 0.001 {
-		//  300 ft. from North pole
-		aa = 89.999
-	} else {
-		if (90.0 + flat) < 0.001 {
-			//  300 ft. from South pole
-			aa = -89.999
-		} else {
-			aa = flat
-		}
-	}
-	argument = aa * dtr
-	clat = math.Cos(argument)
-	argument = flon * dtr
-	// TODO: Why this start from 1?
-	sl[1] = math.Sin(argument)
-	cl[1] = math.Cos(argument)
-	l = 0 // in C index starts from 1
-	n = 0
-	m = 1
-	npq = (nmax * (nmax + 3)) / 2
+	var aa, argument, clat, slat, sd, bb, cc, dd, r float64
+	slat, clat = sinCosLatitude(flat)
 
 	// this block is for geodetic coordinate system ->
 	aa = a2 * clat * clat
@@ -64,6 +120,49 @@ func Shval3(flat, flon, elev float64, nmax int, gha, ghb *[]float64) (float64, f
 	slat = slat*cd - clat*sd
 	clat = clat*cd + aa*sd
 	// <- this block is for geodetic coordinate system
+	return slat, clat, r, cd, sd
+}
+
+// SetGeocentric computes terms of the spherical harmonic sums for geocentric latitude `flat` (decimal degrees)
+// and the distance from the Earth's centre `radius` (km).
+//
+// Field components computed by `Sum` are in the local spherical frame, i.e. X = -B_theta, Y = B_phi, Z = -B_r.
+func (ws *Workspace) SetGeocentric(flat, radius float64, nmax int) {
+	slat, clat := sinCosLatitude(flat)
+	ws.setLegendre(slat, clat, radius, nmax)
+	ws.cd, ws.sd = 1.0, 0.0
+}
+
+// Returns sine and cosine of the latitude `flat` (decimal degrees), the cosine is never zero.
+func sinCosLatitude(flat float64) (float64, float64) {
+	var dtr float64 = 0.01745329
+	var aa float64
+	slat := math.Sin(flat * dtr)
+	if (90.0 - flat) < 0.001 {
+		//  300 ft. from North pole
+		aa = 89.999
+	} else {
+		if (90.0 + flat) < 0.001 {
+			//  300 ft. from South pole
+			aa = -89.999
+		} else {
+			aa = flat
+		}
+	}
+	clat := math.Cos(aa * dtr)
+	return slat, clat
+}
+
+// Computes Legendre functions and radial powers for geocentric sine and cosine of the latitude and radius `r` (km).
+func (ws *Workspace) setLegendre(slat, clat, r float64, nmax int) {
+	var earths_radius float64 = 6371.2
+	var aa, argument, bb, cc, ratio, fn, fm float64
+	var n, m, npq int
+	npq = (nmax * (nmax + 3)) / 2
+	ws.grow(nmax)
+	p, q := ws.p, ws.q
+	n = 0
+	m = 1
 	ratio = earths_radius / r
 	argument = 3.0
 	aa = math.Sqrt(argument)
@@ -81,9 +180,7 @@ func Shval3(flat, flon, elev float64, nmax int, gha, ghb *[]float64) (float64, f
 		if n < m {
 			m = 0
 			n = n + 1
-			argument = ratio
-			power = float64(n + 2)
-			rr = math.Pow(argument, power)
+			ws.rr[n] = math.Pow(ratio, float64(n+2))
 			fn = float64(n)
 		}
 		fm = float64(m)
@@ -94,8 +191,6 @@ func Shval3(flat, flon, elev float64, nmax int, gha, ghb *[]float64) (float64, f
 				j := k - n - 1
 				p[k] = (1.0 + 1.0/fm) * aa * clat * p[j]
 				q[k] = aa * (clat*q[j] + slat/fm*p[j])
-				sl[m] = sl[m-1]*cl[1] + cl[m-1]*sl[1]
-				cl[m] = cl[m-1]*cl[1] - sl[m-1]*sl[1]
 			} else {
 				argument = fn*fn - fm*fm
 				aa = math.Sqrt(argument)
@@ -108,6 +203,65 @@ func Shval3(flat, flon, elev float64, nmax int, gha, ghb *[]float64) (float64, f
 				q[k] = cc*(slat*q[ii]-clat/fn*p[ii]) - bb*q[j]
 			}
 		}
+		m++
+	}
+	ws.slat, ws.clat = slat, clat
+}
+
+// SetLongitude computes terms of the spherical harmonic sums for geodetic longitude `flon` (decimal degrees).
+func (ws *Workspace) SetLongitude(flon float64, nmax int) {
+	ws.grow(nmax)
+	setLongitudeTerms(ws.sl, ws.cl, flon, nmax)
+}
+
+// NewLongitudeTerms returns terms of the spherical harmonic sums for geodetic longitude `flon` (decimal degrees),
+// they could be shared between workspaces, see `SetLongitudeTerms`.
+func NewLongitudeTerms(flon float64, nmax int) *LongitudeTerms {
+	lt := &LongitudeTerms{sl: make([]float64, trigSize(nmax)), cl: make([]float64, trigSize(nmax))}
+	setLongitudeTerms(lt.sl, lt.cl, flon, nmax)
+	return lt
+}
+
+// SetLongitudeTerms copies precomputed longitude terms into the workspace, it's the same as `SetLongitude`.
+func (ws *Workspace) SetLongitudeTerms(lt *LongitudeTerms) {
+	ws.grow(len(lt.sl) - 1)
+	copy(ws.sl, lt.sl)
+	copy(ws.cl, lt.cl)
+}
+
+// Computes sines and cosines of multiples of the longitude `flon` up to `nmax`.
+func setLongitudeTerms(sl, cl []float64, flon float64, nmax int) {
+	var dtr float64 = 0.01745329
+	argument := flon * dtr
+	// TODO: Why this start from 1?
+	sl[1] = math.Sin(argument)
+	cl[1] = math.Cos(argument)
+	for m := 2; m <= nmax; m++ {
+		sl[m] = sl[m-1]*cl[1] + cl[m-1]*sl[1]
+		cl[m] = cl[m-1]*cl[1] - sl[m-1]*sl[1]
+	}
+}
+
+// Sum computes two sets of X, Y, Z for coeffs `gha` and `ghb` at the location set by `SetLatitude` and `SetLongitude`.
+//
+// `nmax` must not exceed the degree used to set the location.
+func (ws *Workspace) Sum(nmax int, gha, ghb *[]float64) (float64, float64, float64, float64, float64, float64) {
+	var x, y, z, xtemp, ytemp, ztemp, aa, aa_temp, bb, cc, rr, fn, fm float64
+	var l, n, m, npq int
+	npq = (nmax * (nmax + 3)) / 2
+	sl, cl, p, q := ws.sl, ws.cl, ws.p, ws.q
+	slat, clat, cd, sd := ws.slat, ws.clat, ws.cd, ws.sd
+	l = 0 // in C index starts from 1
+	n = 0
+	m = 1
+	for k := 1; k <= npq; k++ {
+		if n < m {
+			m = 0
+			n = n + 1
+			rr = ws.rr[n]
+			fn = float64(n)
+		}
+		fm = float64(m)
 		aa = rr * (*gha)[l]
 		aa_temp = rr * (*ghb)[l]
 		if m == 0 {
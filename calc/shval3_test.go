@@ -0,0 +1,34 @@
+package calc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/proway2/go-igrf/coeffs"
+)
+
+func TestShval3HighDegree(t *testing.T) {
+	shc, _ := coeffs.NewCoeffsData()
+	gha, ghb, nmax, _ := shc.Coeffs(2020.5)
+	x, y, z, xtemp, ytemp, ztemp := Shval3(59.9, 39.9, 0.0, nmax, gha, ghb)
+	// degrees 14 and 15 with zero coeffs must not change the result
+	high_nmax := 15
+	padded_a := make([]float64, high_nmax*(high_nmax+2))
+	padded_b := make([]float64, high_nmax*(high_nmax+2))
+	copy(padded_a, *gha)
+	copy(padded_b, *ghb)
+	want := []float64{x, y, z, xtemp, ytemp, ztemp}
+	x, y, z, xtemp, ytemp, ztemp = Shval3(59.9, 39.9, 0.0, high_nmax, &padded_a, &padded_b)
+	got := []float64{x, y, z, xtemp, ytemp, ztemp}
+	for index := range want {
+		if math.Abs(got[index]-want[index]) > 1e-9 {
+			t.Errorf("Shval3() component %v = %v, want %v", index, got[index], want[index])
+		}
+	}
+	// small coeffs of degree 15 produce a small change
+	padded_a[len(padded_a)-1] = 0.1
+	x, _, _, _, _, _ = Shval3(59.9, 39.9, 0.0, high_nmax, &padded_a, &padded_b)
+	if x == want[0] || math.Abs(x-want[0]) > 1.0 {
+		t.Errorf("Shval3() X = %v, want close to but not equal %v", x, want[0])
+	}
+}
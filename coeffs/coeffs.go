@@ -1,7 +1,31 @@
 package coeffs
 
+import "sort"
+
+// IGRF generation used by `NewCoeffsData`.
+const DefaultGeneration = 14
+
+// Embedded coeffs tables by IGRF generation.
+//
+// Only the 14th generation is embedded so far. Tables of earlier generations (igrf11coeffs.txt, igrf12coeffs.txt,
+// igrf13coeffs.txt from https://www.ngdc.noaa.gov/IAGA/vmod/coeffs/) are to be added here as they are,
+// meanwhile they could be used with `LoadFile`.
+var generations = map[int]string{
+	14: igrf14coeffs,
+}
+
+// Generations returns embedded IGRF generations in ascending order.
+func Generations() []int {
+	available := make([]int, 0, len(generations))
+	for generation := range generations {
+		available = append(available, generation)
+	}
+	sort.Ints(available)
+	return available
+}
+
 // taken from https://www.ngdc.noaa.gov/IAGA/vmod/coeffs/igrf14coeffs.txt
-const igrf13coeffs = ` 14th Generation International Geomagnetic Reference Field Schmidt semi-normalised spherical harmonic coefficients, degree n=1,13
+const igrf14coeffs = ` 14th Generation International Geomagnetic Reference Field Schmidt semi-normalised spherical harmonic coefficients, degree n=1,13
 # in units nanoTesla for IGRF and definitive DGRF main-field models (degree n=1,8 nanoTesla/year for secular variation (SV))
 c/s deg ord IGRF IGRF   IGRF   IGRF   IGRF   IGRF   IGRF   IGRF   IGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF   DGRF     DGRF      DGRF      DGRF      IGRF        SV
 g/h n m 1900.0 1905.0 1910.0 1915.0 1920.0 1925.0 1930.0 1935.0 1940.0 1945.0 1950.0 1955.0 1960.0 1965.0 1970.0 1975.0 1980.0 1985.0 1990.0 1995.0   2000.0    2005.0    2010.0    2015.0    2020.0   2025.0 2025-30
@@ -0,0 +1,116 @@
+package coeffs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// a period of validity of the World Magnetic Model, the `.COF` header has no end date,
+// the model is specified for 5 years from its epoch as in the WMM report and NOAA software
+const cof_interval = 5.0
+
+// LoadCOF returns a SHC data structure populated from the World Magnetic Model coefficients
+// in NOAA `.COF` format read from `r`, e.g. WMM.COF or WMMHR.COF.
+//
+// The model has a single epoch and a linear SV, coeffs for a date are computed as coeffs + (date - epoch) * SV.
+// The returned coeffs are valid from the epoch to the epoch plus 5 years.
+func LoadCOF(r io.Reader) (*IGRFcoeffs, error) {
+	raw_data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read coeffs: %w", err)
+	}
+	igrf := IGRFcoeffs{data: &map[string]*epochData{}}
+	if err := igrf.readCOF(string(raw_data)); err != nil {
+		return nil, err
+	}
+	return &igrf, nil
+}
+
+// LoadCOFFile returns a SHC data structure populated from the World Magnetic Model `.COF` file at `path`.
+func LoadCOFFile(path string) (*IGRFcoeffs, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCOF(f)
+}
+
+// Populates the existing `IGRFcoeffs` structure from the `raw_data` in `.COF` format.
+//
+// The first line is the header: epoch, model name and release date,
+// every next line is `n m g h g_sv h_sv`, the data ends with a line of nines.
+func (igrf *IGRFcoeffs) readCOF(raw_data string) error {
+	line_provider := coeffsLineProvider(raw_data)
+	defer drainLines(line_provider)
+
+	header, ok := <-line_provider
+	if !ok {
+		return errors.New("unable to get epoch, COF header is missing")
+	}
+	header_data := space_re.Split(header, -1)
+	epoch, err := strconv.ParseFloat(header_data[0], 64)
+	if err != nil || len(header_data) < 2 {
+		return fmt.Errorf("COF header is incorrect: %v", header)
+	}
+	igrf.name = header_data[1]
+	igrf.names = &[]string{fmt.Sprintf("%v %v", igrf.name, header_data[0]), fmt.Sprintf("SV %v", epoch2string(epoch+cof_interval))}
+	igrf.epochs = &[]float64{epoch, epoch + cof_interval}
+	main_field := &epochData{coeffs: &[]float64{}}
+	sv_field := &epochData{coeffs: &[]float64{}}
+	(*igrf.data)[epoch2string(epoch)] = main_field
+	igrf.sv = sv_field
+	var i int = 0
+	for line := range line_provider {
+		if strings.HasPrefix(line, "9999") {
+			break
+		}
+		line_data := space_re.Split(line, -1)
+		if len(line_data) != 6 {
+			return fmt.Errorf("unable to parse coeffs, line %v has %v columns, expected %v", i+1, len(line_data), 6)
+		}
+		degree, g_index, err := parseCoeffIndex("g", line_data[0], line_data[1])
+		if err != nil {
+			return fmt.Errorf("unable to parse coeffs, line %v: %w", i+1, err)
+		}
+		values, err := parseArrayToFloat(line_data[2:])
+		if err != nil {
+			return err
+		}
+		g, h, g_sv, h_sv := (*values)[0], (*values)[1], (*values)[2], (*values)[3]
+		setCoeff(main_field.coeffs, g_index, g)
+		setCoeff(sv_field.coeffs, g_index, g_sv)
+		if g_index != degree*degree-1 {
+			// h coeffs follow g coeffs of the same order, there is no h for the zero order
+			setCoeff(main_field.coeffs, g_index+1, h)
+			setCoeff(sv_field.coeffs, g_index+1, h_sv)
+		}
+		if (g != 0 || h != 0) && degree > main_field.nmax {
+			main_field.nmax = degree
+		}
+		if (g_sv != 0 || h_sv != 0) && degree > sv_field.nmax {
+			sv_field.nmax = degree
+		}
+		if degree > igrf.nmax {
+			igrf.nmax = degree
+		}
+		i++
+	}
+	if i == 0 {
+		return errors.New("unable to parse coeffs, no coeffs found")
+	}
+	for _, epoch_data := range []*epochData{main_field, sv_field} {
+		resizeCoeffs(epoch_data.coeffs, igrf.nmax*(igrf.nmax+2))
+	}
+	return nil
+}
+
+// Sets the coeff with the `index`, `coeffs` are extended with zeros if needed.
+func setCoeff(coeffs *[]float64, index int, value float64) {
+	resizeCoeffs(coeffs, index+1)
+	(*coeffs)[index] = value
+}
@@ -0,0 +1,148 @@
+package coeffs
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Produces `.COF` data from the IGRF-14 coeffs for 2025.0 and its SV.
+func igrf14COF() string {
+	igrf, _ := NewCoeffsData()
+	main_field := *(*igrf.data)["2025.0"].coeffs
+	sv_field := *(*igrf.data)["2030.0"].coeffs
+	var builder strings.Builder
+	builder.WriteString("    2025.0            IGRF-COF     11/13/2024\n")
+	for n := 1; n <= 13; n++ {
+		for m := 0; m <= n; m++ {
+			_, g_index, _ := parseCoeffIndex("g", fmt.Sprint(n), fmt.Sprint(m))
+			var h, h_sv float64
+			g := main_field[g_index]
+			g_sv := (sv_field[g_index] - g) / cof_interval
+			if m > 0 {
+				h = main_field[g_index+1]
+				h_sv = (sv_field[g_index+1] - h) / cof_interval
+			}
+			builder.WriteString(fmt.Sprintf("%3d%3d%10.2f%10.2f%10.2f%10.2f\n", n, m, g, h, g_sv, h_sv))
+		}
+	}
+	builder.WriteString("999999999999999999999999999999999999999999999999\n")
+	builder.WriteString("999999999999999999999999999999999999999999999999\n")
+	return builder.String()
+}
+
+func TestLoadCOF(t *testing.T) {
+	igrf, _ := NewCoeffsData()
+	wmm, err := LoadCOF(strings.NewReader(igrf14COF()))
+	if err != nil {
+		t.Fatalf("LoadCOF() error = %v", err)
+	}
+	if wmm.Name() != "IGRF-COF" {
+		t.Errorf("Name() = %v, want %v", wmm.Name(), "IGRF-COF")
+	}
+	if start, end := wmm.ValidRange(); start != 2025.0 || end != 2030.0 {
+		t.Errorf("ValidRange() = (%v, %v), want (%v, %v)", start, end, 2025.0, 2030.0)
+	}
+	main_field, _, want_nmax, _ := igrf.Coeffs(2025.0)
+	sv_field := *(*igrf.data)["2030.0"].coeffs
+	// mid-year dates of leap and common years, SV is linear in decimal years
+	for _, date := range []float64{2025.0, 2026.3, 2028.5, 2029.9, 2030.0} {
+		got1, got2, got_nmax, err := wmm.Coeffs(date)
+		if err != nil {
+			t.Fatalf("Coeffs(%v) error = %v", date, err)
+		}
+		got, got_sv, _, err := wmm.CoeffsSV(date)
+		if err != nil {
+			t.Fatalf("CoeffsSV(%v) error = %v", date, err)
+		}
+		if got_nmax != want_nmax {
+			t.Errorf("Coeffs(%v) nmax = %v, want %v", date, got_nmax, want_nmax)
+		}
+		for index := range *main_field {
+			sv := (*got_sv)[index]
+			want1 := (*main_field)[index] + (date-2025.0)*sv
+			want2 := want1 + sv
+			if math.Abs((*got1)[index]-want1) > 1e-9 || math.Abs((*got2)[index]-want2) > 1e-9 || (*got)[index] != (*got1)[index] {
+				t.Errorf("Coeffs(%v) coeff %v = (%v, %v), want (%v, %v)", date, index, (*got1)[index], (*got2)[index], want1, want2)
+			}
+			// SV is written with 2 decimals
+			if want_sv := (sv_field[index] - (*main_field)[index]) / cof_interval; math.Abs(sv-want_sv) > 0.005 {
+				t.Errorf("CoeffsSV(%v) SV %v = %v, want %v", date, index, sv, want_sv)
+			}
+		}
+	}
+	if _, _, _, err := wmm.Coeffs(2024.9); err == nil {
+		t.Errorf("Coeffs() expected an error for the date before the epoch")
+	}
+}
+
+// Produces `.COF` data of the degree `nmax` like WMMHR, coeffs are decreasing with the degree.
+func TestLoadCOFHighDegree(t *testing.T) {
+	// the main field and SV of IGRF-14 up to the degree 13 extended by small terms to the degree 133 like WMMHR
+	wmm, err := LoadCOFFile(filepath.Join("..", "testdata", "IGRF14-HR.COF"))
+	if err != nil {
+		t.Fatalf("LoadCOFFile() error = %v", err)
+	}
+	if wmm.NMax() != 133 {
+		t.Errorf("NMax() = %v, want 133", wmm.NMax())
+	}
+	got, got_sv, nmax, err := wmm.CoeffsSV(2027.5)
+	if err != nil {
+		t.Fatalf("CoeffsSV() error = %v", err)
+	}
+	if nmax != 133 || len(*got) != 133*135 || len(*got_sv) != 133*135 {
+		t.Fatalf("CoeffsSV() nmax = %v with %v coeffs and %v SV, want 133 with %v", nmax, len(*got), len(*got_sv), 133*135)
+	}
+	// g(133,133) and h(133,133) are the last ones
+	want := []float64{0.01 + 2.5*0.01, -0.01 - 2.5*0.01}
+	for index, value := range (*got)[133*135-2:] {
+		if math.Abs(value-want[index]) > 1e-9 {
+			t.Errorf("CoeffsSV() coeff %v = %v, want %v", 133*135-2+index, value, want[index])
+		}
+	}
+	// low degrees are the same as IGRF-14 at the epoch
+	igrf, _ := NewCoeffsData()
+	want_low, _, _, _ := igrf.Coeffs(2025.0)
+	got, _, _, _ = wmm.Coeffs(2025.0)
+	for index, value := range *want_low {
+		if math.Abs((*got)[index]-value) > 1e-3 {
+			t.Errorf("Coeffs() coeff %v = %v, want %v", index, (*got)[index], value)
+		}
+	}
+}
+
+func TestLoadCOFErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "Empty input", raw: ""},
+		{name: "Incorrect header", raw: "WMM-2025 2025.0\n  1  0  -29351.8       0.0       12.0        0.0\n"},
+		{name: "No coeffs", raw: "    2025.0            WMM-2025     11/13/2024\n999999999999999999999999999999999999999999999999\n"},
+		{name: "Missing column", raw: "    2025.0            WMM-2025     11/13/2024\n  1  0  -29351.8       0.0       12.0\n"},
+		{name: "Incorrect order", raw: "    2025.0            WMM-2025     11/13/2024\n  1  2  -29351.8       0.0       12.0        0.0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadCOF(strings.NewReader(tt.raw)); err == nil {
+				t.Errorf("LoadCOF() expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadCOFFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "WMM.COF")
+	if err := os.WriteFile(path, []byte(igrf14COF()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCOFFile(path); err != nil {
+		t.Errorf("LoadCOFFile() error = %v", err)
+	}
+	if _, err := LoadCOFFile(filepath.Join(t.TempDir(), "missing.COF")); err == nil {
+		t.Errorf("LoadCOFFile() expected an error for a missing file")
+	}
+}
@@ -3,29 +3,36 @@ package coeffs
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"regexp"
+	"sort"
 	"strconv"
 )
 
-// max possible spherical harmonic degree
+// max possible spherical harmonic degree of the embedded IGRF model
+//
+// Deprecated: the degree is derived from the coeffs, use `IGRFcoeffs.NMax` instead.
 const N_MAX = 13
 
-// number of lines (with data) in the coeffs file
-const coeffs_lines = N_MAX * (N_MAX + 2)
-
-// an interval between epochs in the coeffs file, e.g. (1950.0 - 1945.0 = 5)
-const interval = 5
-
 var (
 	space_re   *regexp.Regexp = regexp.MustCompile(`\s+`)
-	year_sv_re *regexp.Regexp = regexp.MustCompile(`\d{4}-\d{2}`)
+	year_sv_re *regexp.Regexp = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
 )
 
 type IGRFcoeffs struct {
 	names  *[]string
 	epochs *[]float64
 	data   *map[string]*epochData
+	// max spherical harmonic degree among all epochs
+	nmax int
+	// IGRF generation of the embedded coeffs, 0 for the loaded ones
+	generation int
+	// name of the model, e.g. IGRF-14 or WMM-2025
+	name string
+	// SV (nT/yr) of a model with a single epoch and linear SV, e.g. WMM, nil for IGRF
+	sv *epochData
 }
 
 type epochData struct {
@@ -33,10 +40,51 @@ type epochData struct {
 	coeffs *[]float64
 }
 
-// Returns an initialized IGRF SHC data structure.
+// Returns an initialized IGRF SHC data structure of the `DefaultGeneration`.
 func NewCoeffsData() (*IGRFcoeffs, error) {
-	igrf := IGRFcoeffs{data: &map[string]*epochData{}}
-	if err := igrf.readCoeffs(); err != nil {
+	return NewCoeffsDataForGeneration(DefaultGeneration)
+}
+
+// NewCoeffsDataForGeneration returns an initialized IGRF SHC data structure
+// for the given embedded IGRF `generation`, see `Generations`.
+func NewCoeffsDataForGeneration(generation int) (*IGRFcoeffs, error) {
+	raw_data, ok := generations[generation]
+	if !ok {
+		return nil, fmt.Errorf("IGRF generation %v is not available, available generations: %v", generation, Generations())
+	}
+	igrf, err := parseCoeffs(raw_data)
+	if err != nil {
+		return nil, err
+	}
+	igrf.generation = generation
+	igrf.name = fmt.Sprintf("IGRF-%v", generation)
+	return igrf, nil
+}
+
+// Load returns an IGRF SHC data structure populated from an IGRF-format coefficients table read from `r`,
+// e.g. https://www.ngdc.noaa.gov/IAGA/vmod/coeffs/igrf14coeffs.txt.
+func Load(r io.Reader) (*IGRFcoeffs, error) {
+	raw_data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read coeffs: %w", err)
+	}
+	return parseCoeffs(string(raw_data))
+}
+
+// LoadFile returns an IGRF SHC data structure populated from an IGRF-format coefficients file at `path`.
+func LoadFile(path string) (*IGRFcoeffs, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Returns an IGRF SHC data structure populated from the `raw_data` coeffs table.
+func parseCoeffs(raw_data string) (*IGRFcoeffs, error) {
+	igrf := IGRFcoeffs{data: &map[string]*epochData{}, name: "IGRF"}
+	if err := igrf.readCoeffs(raw_data); err != nil {
 		return nil, err
 	}
 	return &igrf, nil
@@ -51,21 +99,112 @@ func (igrf *IGRFcoeffs) Coeffs(date float64) (*[]float64, *[]float64, int, error
 	if date < min_epoch || date > max_epoch {
 		return nil, nil, 0, fmt.Errorf("date %v is out of range (%v, %v)", date, min_epoch, max_epoch)
 	}
+	if igrf.sv != nil {
+		coeffs_start, nmax := igrf.linearCoeffs(date)
+		coeffs_end, _ := igrf.linearCoeffs(date + 1)
+		return coeffs_start, coeffs_end, nmax, nil
+	}
 	// calculate coeffs for the requested date
 	start, end := igrf.findEpochs(date)
+	var coeffs_start *[]float64
 	var nmax int
-	coeffs_start, nmax := igrf.interpolateCoeffs(start, end, date)
+	if date < max_epoch {
+		coeffs_start, nmax = igrf.interpolateCoeffs(start, end, date)
+	} else {
+		coeffs_start, nmax = igrf.extrapolateCoeffs(start, end, date)
+	}
 	// in order to calculate yearly SV add 1 year to the date
 	date = date + 1
 	var coeffs_end *[]float64
 	if date < max_epoch {
 		coeffs_end, nmax = igrf.interpolateCoeffs(start, end, date)
 	} else {
-		coeffs_end = igrf.extrapolateCoeffs(start, end, date)
+		coeffs_end, _ = igrf.extrapolateCoeffs(start, end, date)
 	}
 	return coeffs_start, coeffs_end, nmax, nil
 }
 
+// CoeffsSV returns a set of SH coeffs for the given `date` and their SV (nT/yr), as well as the maximal spherical harmonic degree.
+//
+// SV is the time derivative of coeffs interpolated the same way as by `Coeffs`, i.e. with respect to leap years,
+// so it slightly differs from the rate of change between epochs, e.g. it's higher in leap years.
+// For dates at or beyond the last epoch the rate between two last epochs is used.
+func (igrf *IGRFcoeffs) CoeffsSV(date float64) (*[]float64, *[]float64, int, error) {
+	max_column := len(*igrf.epochs)
+	min_epoch := (*igrf.epochs)[0]
+	max_epoch := (*igrf.epochs)[max_column-1]
+	if date < min_epoch || date > max_epoch {
+		return nil, nil, 0, fmt.Errorf("date %v is out of range (%v, %v)", date, min_epoch, max_epoch)
+	}
+	if igrf.sv != nil {
+		coeffs, nmax := igrf.linearCoeffs(date)
+		sv := make([]float64, len(*igrf.sv.coeffs))
+		copy(sv, *igrf.sv.coeffs)
+		return coeffs, &sv, nmax, nil
+	}
+	start, end := igrf.findEpochs(date)
+	if date < max_epoch {
+		factor, err := findDateFactor(start, end, date)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		rate, err := findDateFactorRate(start, end, date)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		coeffs, sv, nmax := igrf.blendCoeffs(start, end, factor, rate)
+		return coeffs, sv, nmax, nil
+	}
+	coeffs, nmax := igrf.extrapolateCoeffs(start, end, date)
+	dte1, _ := strconv.ParseFloat(start, 64)
+	dte2, _ := strconv.ParseFloat(end, 64)
+	coeffs_start := (*igrf.data)[start].coeffs
+	coeffs_end := (*igrf.data)[end].coeffs
+	sv := make([]float64, len(*coeffs))
+	if dte2 > dte1 {
+		for i := range sv {
+			sv[i] = ((*coeffs_end)[i] - (*coeffs_start)[i]) / (dte2 - dte1)
+		}
+	}
+	return coeffs, &sv, nmax, nil
+}
+
+// Generation returns the IGRF generation of the embedded coeffs, or 0 if coeffs are loaded by `Load` or `LoadFile`.
+func (igrf *IGRFcoeffs) Generation() int {
+	return igrf.generation
+}
+
+// Name returns the name of the model, e.g. IGRF-14, or IGRF for the loaded IGRF coeffs, or WMM-2025.
+func (igrf *IGRFcoeffs) Name() string {
+	return igrf.name
+}
+
+// ValidRange returns the first and the last epochs of the coeffs, i.e. the range of valid dates.
+func (igrf *IGRFcoeffs) ValidRange() (float64, float64) {
+	return (*igrf.epochs)[0], (*igrf.epochs)[len(*igrf.epochs)-1]
+}
+
+// NMax returns the maximal spherical harmonic degree of the coeffs.
+func (igrf *IGRFcoeffs) NMax() int {
+	return igrf.nmax
+}
+
+// Computes a set of SH coeffs and the maximal spherical harmonic degree for a given `date`
+// of the model with linear SV: coeffs + (date - epoch) * sv.
+func (igrf *IGRFcoeffs) linearCoeffs(date float64) (*[]float64, int) {
+	epoch := (*igrf.epochs)[0]
+	main_field := (*igrf.data)[epoch2string(epoch)]
+	values := make([]float64, len(*main_field.coeffs))
+	for i, coeff := range *main_field.coeffs {
+		values[i] = coeff + (date-epoch)*(*igrf.sv.coeffs)[i]
+	}
+	nmax := main_field.nmax
+	if igrf.sv.nmax > nmax {
+		nmax = igrf.sv.nmax
+	}
+	return &values, nmax
+}
+
 // Computes a set of SH coeffs and the maximal spherical harmonic degree
 // for a given `date` and `start_epoch`, `end_epoch`.
 //
@@ -75,13 +214,23 @@ func (igrf *IGRFcoeffs) interpolateCoeffs(start_epoch, end_epoch string, date fl
 	if err != nil {
 		log.Fatal("Epochs are incorrect!")
 	}
+	values, _, nmax := igrf.blendCoeffs(start_epoch, end_epoch, factor, 0)
+	return values, nmax
+}
+
+// Computes a set of SH coeffs between `start_epoch` and `end_epoch` for the interpolation `factor`,
+// their derivatives with respect to the date for the `rate` of the factor (see `findDateFactorRate`)
+// and the maximal spherical harmonic degree.
+func (igrf *IGRFcoeffs) blendCoeffs(start_epoch, end_epoch string, factor, rate float64) (*[]float64, *[]float64, int) {
 	coeffs_start := (*igrf.data)[start_epoch].coeffs
 	coeffs_end := (*igrf.data)[end_epoch].coeffs
 	values := make([]float64, len(*coeffs_start))
+	rates := make([]float64, len(*coeffs_start))
 	nmax1 := (*igrf.data)[start_epoch].nmax
 	nmax2 := (*igrf.data)[end_epoch].nmax
 	var k, l, nmax int
-	var interp func(float64, float64, float64) float64
+	// the value and its derivative with respect to the factor within coeffs k to l
+	var interp func(float64, float64, float64) (float64, float64)
 	if nmax1 == nmax2 {
 		// before 2000.0
 		k = nmax1 * (nmax1 + 2)
@@ -89,143 +238,147 @@ func (igrf *IGRFcoeffs) interpolateCoeffs(start_epoch, end_epoch string, date fl
 		nmax = nmax1
 	} else {
 		if nmax1 > nmax2 {
-			// the last column has degree of 8
-			// now it's anything after 2025.0
+			// the end epoch has lower degree, e.g. the last column is computed from SV
 			k = nmax2 * (nmax2 + 2)
 			l = nmax1 * (nmax1 + 2)
-			interp = func(start, end, f float64) float64 {
-				return start
+			interp = func(start, end, f float64) (float64, float64) {
+				return start, 0
 			}
 			nmax = nmax1
 		} else {
-			// between 1995.0 and 2000.0
+			// the end epoch has higher degree, e.g. between 1995.0 and 2000.0
 			k = nmax1 * (nmax1 + 2)
 			l = nmax2 * (nmax2 + 2)
-			interp = func(_, end, f float64) float64 {
-				return f * end
+			interp = func(_, end, f float64) (float64, float64) {
+				return f * end, end
 			}
 			nmax = nmax2
 		}
 	}
-	for i := 0; i < coeffs_lines; i++ {
+	for i := 0; i < len(values); i++ {
 		coeff_start := (*coeffs_start)[i]
 		coeff_end := (*coeffs_end)[i]
-		var value float64
+		var value, derivative float64
 		if i >= k && i < l {
-			value = interp(coeff_start, coeff_end, factor)
+			value, derivative = interp(coeff_start, coeff_end, factor)
 		} else {
 			value = coeff_start + factor*(coeff_end-coeff_start)
+			derivative = coeff_end - coeff_start
 		}
 		values[i] = value
+		rates[i] = derivative * rate
 	}
-	return &values, nmax
+	return &values, &rates, nmax
 }
 
 // Computes a set of SH coeffs and the maximal spherical harmonic degree
 // for a given `date` and `start_epoch`, `end_epoch`.
 //
-// `date` is beyond than the maximal possible epoch.
-func (igrf *IGRFcoeffs) extrapolateCoeffs(start_epoch, end_epoch string, date float64) *[]float64 {
-	dte1, _ := strconv.ParseFloat(start_epoch, 32)
-	factor := date - dte1
+// `date` is not less than the maximal possible epoch, so coeffs are linearly extrapolated
+// with the rate of change between `start_epoch` and `end_epoch`.
+func (igrf *IGRFcoeffs) extrapolateCoeffs(start_epoch, end_epoch string, date float64) (*[]float64, int) {
+	dte1, _ := strconv.ParseFloat(start_epoch, 64)
+	dte2, _ := strconv.ParseFloat(end_epoch, 64)
+	var factor float64
+	if dte2 > dte1 {
+		factor = (date - dte1) / (dte2 - dte1)
+	}
 	coeffs_start := (*igrf.data)[start_epoch].coeffs
 	coeffs_end := (*igrf.data)[end_epoch].coeffs
 	nmax1 := (*igrf.data)[start_epoch].nmax
 	nmax2 := (*igrf.data)[end_epoch].nmax
-	if nmax1 <= nmax2 {
-		return nil // error here?
+	nmax := nmax1
+	if nmax2 > nmax1 {
+		nmax = nmax2
 	}
-	var k, l int
-	k = nmax2 * (nmax2 + 2)
-	l = nmax1 * (nmax1 + 2)
 	values := make([]float64, len(*coeffs_start))
-	for i := 0; i < coeffs_lines; i++ {
+	for i := 0; i < len(values); i++ {
 		coeff_start := (*coeffs_start)[i]
 		coeff_end := (*coeffs_end)[i]
-		var value float64
-		if i >= k && i < l {
-			value = coeff_start
-		} else {
-			sv := (coeff_end - coeff_start) / interval
-			value = coeff_start + factor*sv
-		}
-		values[i] = value
+		values[i] = coeff_start + factor*(coeff_end-coeff_start)
 	}
-	return &values
+	return &values, nmax
 }
 
-// Calculates start and end epochs for a given date,
-// default `interval` is used for borders and for multiplicity
+// Calculates start and end epochs for a given date, epochs are not required to be evenly spaced.
+//
+// For dates at or beyond the maximal epoch the last two epochs are returned.
 func (igrf *IGRFcoeffs) findEpochs(date float64) (string, string) {
-	max_column := len(*igrf.epochs)
-	min_epoch := (*igrf.epochs)[0]
-	max_epoch := (*igrf.epochs)[max_column-1]
-	var start_epoch, end_epoch string
-	if date >= max_epoch {
-		start_epoch = epoch2string(max_epoch)
-		end_epoch = epoch2string(max_epoch + interval)
-		return start_epoch, end_epoch
-	}
-	col1 := min_epoch + float64(int((date-min_epoch)/interval))*interval
-	start_epoch = epoch2string(col1)
-	col2 := col1 + interval
-	end_epoch = epoch2string(col2)
-	return start_epoch, end_epoch
+	epochs := *igrf.epochs
+	max_column := len(epochs)
+	if max_column < 2 {
+		return epoch2string(epochs[0]), epoch2string(epochs[0])
+	}
+	// index of the last epoch that is not after `date`, but not the last epoch
+	index := sort.Search(max_column, func(i int) bool { return epochs[i] > date }) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > max_column-2 {
+		index = max_column - 2
+	}
+	return epoch2string(epochs[index]), epoch2string(epochs[index+1])
 }
 
-// The main function that populates the existing `IGRFcoeffs` structure.
-func (igrf *IGRFcoeffs) readCoeffs() error {
-	line_provider := coeffsLineProvider()
+// The main function that populates the existing `IGRFcoeffs` structure from the `raw_data` coeffs table.
+func (igrf *IGRFcoeffs) readCoeffs(raw_data string) error {
+	line_provider := coeffsLineProvider(raw_data)
+	// the provider must be drained, otherwise its goroutine is never released
+	defer drainLines(line_provider)
 
 	var err error
-	igrf.names, igrf.epochs, err = getEpochs(line_provider)
+	var sv_interval float64
+	igrf.names, igrf.epochs, sv_interval, err = getEpochs(line_provider)
 	if err != nil {
 		return err
 	}
+	if err := checkEpochs(*igrf.epochs); err != nil {
+		return err
+	}
 	// initializing the map
 	for _, epoch := range *igrf.epochs {
-		local_arr := make([]float64, coeffs_lines)
+		local_arr := make([]float64, 0)
 		(*igrf.data)[epoch2string(epoch)] = &epochData{coeffs: &local_arr}
 	}
-	err = igrf.getCoeffsForEpochs(line_provider)
-	if err != nil {
-		return err
-	}
-	for epoch := range *igrf.data {
-		nmax, err := nMaxForEpoch(epoch)
-		if err != nil {
-			return err
-		}
-		(*igrf.data)[epoch].nmax = nmax
-	}
-	return nil
+	return igrf.getCoeffsForEpochs(line_provider, sv_interval)
 }
 
 // Finds lines with epochs, parses these lines and returns arrays of epochs: names and floats.
-func getEpochs(reader <-chan string) (*[]string, *[]float64, error) {
+// Also returns the interval covered by the SV column, or 0 if there is no SV column.
+func getEpochs(reader <-chan string) (*[]string, *[]float64, float64, error) {
 	cs_re := regexp.MustCompile(`^c/s.*`)
 	for line := range reader {
 		if !cs_re.Match([]byte(line)) {
 			continue
 		}
-		line2 := <-reader
-		names, epochs := parseHeader(line, line2)
-		return &names, &epochs, nil
+		line2, ok := <-reader
+		if !ok {
+			return nil, nil, 0, errors.New("unable to get epochs, coeffs header is incomplete")
+		}
+		names, epochs, sv_interval, err := parseHeader(line, line2)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		return &names, &epochs, sv_interval, nil
 	}
-	return nil, nil, errors.New("unable to get epochs")
+	return nil, nil, 0, errors.New("unable to get epochs")
 }
 
 // Parses the header of the coeffs. Usually it's the first two non-comment lines.
-func parseHeader(line1, line2 string) ([]string, []float64) {
+//
+// The last column might contain SV instead of coeffs, e.g. `2025-30`. Its epoch is the end of the SV interval
+// and the length of this interval is returned as well.
+func parseHeader(line1, line2 string) ([]string, []float64, float64, error) {
 	line1_data := space_re.Split(line1, -1)
 	line2_data := space_re.Split(line2, -1)
 
 	if len(line1_data) != len(line2_data) {
-		log.Fatal("Coeffs header is incorrect.")
+		return nil, nil, 0, errors.New("coeffs header is incorrect")
 	}
 	names := make([]string, len(line1_data))
 	epochs := make([]float64, len(line1_data))
 	var shift int
+	var sv_interval float64
 	for index := range line1_data {
 		raw_epoch := line2_data[index]
 		name := fmt.Sprintf("%v %v", line1_data[index], raw_epoch)
@@ -237,57 +390,96 @@ func parseHeader(line1, line2 string) ([]string, []float64) {
 			}
 		}
 		// this is the last column
-		if year_sv_re.Match([]byte(raw_epoch)) {
-			last_digits := raw_epoch[5:]
-			decades, err := strconv.ParseFloat(last_digits, 32)
-			if err != nil {
-				log.Fatal(err.Error())
+		if sv_years := year_sv_re.FindStringSubmatch(raw_epoch); sv_years != nil {
+			if index != len(line1_data)-1 {
+				return nil, nil, 0, errors.New("coeffs header is incorrect, SV column must be the last one")
 			}
-			epoch := 2000.0 + decades
-			epochs[index] = epoch
+			start_year, _ := strconv.Atoi(sv_years[1])
+			end_year, _ := strconv.Atoi(sv_years[2])
+			// only the last two digits of the year are given
+			end_year += start_year - start_year%100
+			if end_year <= start_year {
+				end_year += 100
+			}
+			sv_interval = float64(end_year - start_year)
+			epochs[index] = float64(end_year)
+		}
+	}
+	if shift == 0 {
+		return nil, nil, 0, errors.New("coeffs header contains no epochs")
+	}
+	return names, epochs[shift:], sv_interval, nil
+}
+
+// Checks that epochs are in ascending order.
+func checkEpochs(epochs []float64) error {
+	for i := 1; i < len(epochs); i++ {
+		if epochs[i] <= epochs[i-1] {
+			return fmt.Errorf("epochs are not in ascending order: %v, %v", epochs[i-1], epochs[i])
 		}
 	}
-	return names, epochs[shift:]
+	return nil
 }
 
 // Parses lines with coefficients and populates the `IGRFcoeffs` structure.
-func (igrf *IGRFcoeffs) getCoeffsForEpochs(provider <-chan string) error {
+//
+// Every line is identified by its `g/h n m` columns, so the order of lines doesn't matter.
+// The maximal spherical harmonic degree of every epoch is the highest degree with non-zero coeffs.
+// If `sv_interval` is not zero, the last column is SV and it's converted into coeffs.
+func (igrf *IGRFcoeffs) getCoeffsForEpochs(provider <-chan string, sv_interval float64) error {
+	columns := len(*igrf.epochs)
 	var i int = 0
 	for line := range provider {
 		line_data := space_re.Split(line, -1)
+		if len(line_data) != columns+3 {
+			return fmt.Errorf("unable to parse coeffs, line %v has %v columns, expected %v", i+1, len(line_data), columns+3)
+		}
+		degree, index, err := parseCoeffIndex(line_data[0], line_data[1], line_data[2])
+		if err != nil {
+			return fmt.Errorf("unable to parse coeffs, line %v: %w", i+1, err)
+		}
 		line_coeffs, err := parseArrayToFloat(line_data[3:])
 		if err != nil {
 			return errors.New("unable to parse coeffs")
 		}
-		igrf.loadCoeffs(i, line_coeffs)
+		if sv_interval != 0 {
+			// real value calculated for the SV column
+			sv := (*line_coeffs)[columns-1]
+			(*line_coeffs)[columns-1] = (*line_coeffs)[columns-2] + sv*sv_interval
+		}
+		igrf.loadCoeffs(index, line_coeffs)
+		// SV column is checked against raw values, as its coeffs are derived from the previous column
+		for column, token := range line_data[3:] {
+			if value, _ := strconv.ParseFloat(token, 64); value == 0 {
+				continue
+			}
+			epoch_data := (*igrf.data)[epoch2string((*igrf.epochs)[column])]
+			if degree > epoch_data.nmax {
+				epoch_data.nmax = degree
+			}
+		}
+		if degree > igrf.nmax {
+			igrf.nmax = degree
+		}
 		i++
 	}
+	if i == 0 {
+		return errors.New("unable to parse coeffs, no coeffs found")
+	}
+	// all epochs share the same number of coeffs, missing ones are zeros
+	for _, epoch_data := range *igrf.data {
+		resizeCoeffs(epoch_data.coeffs, igrf.nmax*(igrf.nmax+2))
+	}
 	return nil
 }
 
 // Populates the corresponding fields inside the `IGRFcoeffs` structure with actual coeffs.
-func (igrf *IGRFcoeffs) loadCoeffs(line_num int, line_coeffs *[]float64) {
-	for index, coeff := range *line_coeffs {
-		epoch := (*igrf.epochs)[index]
+func (igrf *IGRFcoeffs) loadCoeffs(index int, line_coeffs *[]float64) {
+	for column, coeff := range *line_coeffs {
+		epoch := (*igrf.epochs)[column]
 		epoch_str := epoch2string(epoch)
-		(*(*igrf.data)[epoch_str].coeffs)[line_num] = coeff
-	}
-}
-
-// Returns max spherical harmonic degree for a certain epoch.
-func nMaxForEpoch(epoch string) (int, error) {
-	// this is hardcoded
-	var nmax int
-	epoch_f, err := strconv.ParseFloat(epoch, 32)
-	if err != nil {
-		return 0, err
-	}
-	if epoch_f < 2000.0 {
-		nmax = 10
-	} else if epoch_f > 2025.0 {
-		nmax = 8
-	} else {
-		nmax = 13
+		coeffs := (*igrf.data)[epoch_str].coeffs
+		resizeCoeffs(coeffs, index+1)
+		(*coeffs)[index] = coeff
 	}
-	return nmax, nil
 }
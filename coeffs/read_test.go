@@ -1,7 +1,12 @@
 package coeffs
 
 import (
+	"fmt"
 	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -131,3 +136,267 @@ func TestIGRFcoeffs_Coeffs(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad(t *testing.T) {
+	embedded, _ := NewCoeffsData()
+	// loaded coeffs are not bound to any generation
+	embedded.generation = 0
+	embedded.name = "IGRF"
+	header := "c/s deg ord IGRF IGRF SV\ng/h n m 2015.0 2020.0 2020-25\n"
+	tests := []struct {
+		name    string
+		raw     string
+		want    *IGRFcoeffs
+		wantErr bool
+	}{
+		{
+			name:    "The embedded coeffs table",
+			raw:     igrf14coeffs,
+			want:    embedded,
+			wantErr: false,
+		},
+		{
+			name:    "Windows line endings and blank lines",
+			raw:     strings.ReplaceAll(igrf14coeffs, "\n", "\r\n\r\n"),
+			want:    embedded,
+			wantErr: false,
+		},
+		{
+			name:    "Empty input",
+			raw:     "",
+			wantErr: true,
+		},
+		{
+			name:    "Header only",
+			raw:     header,
+			wantErr: true,
+		},
+		{
+			name:    "Incomplete header",
+			raw:     "c/s deg ord IGRF IGRF SV",
+			wantErr: true,
+		},
+		{
+			name:    "Header columns mismatch",
+			raw:     "c/s deg ord IGRF IGRF SV\ng/h n m 2015.0 2020.0\ng 1 0 1.0 2.0 0.1\n",
+			wantErr: true,
+		},
+		{
+			name:    "Coeffs columns mismatch",
+			raw:     header + "g 1 0 1.0 2.0\n",
+			wantErr: true,
+		},
+		{
+			name:    "Incorrect order",
+			raw:     header + "g 1 2 1.0 2.0 0.1\n",
+			wantErr: true,
+		},
+		{
+			name:    "Epochs are not ascending",
+			raw:     "c/s deg ord IGRF IGRF\ng/h n m 2020.0 2015.0\ng 1 0 1.0 2.0\n",
+			wantErr: true,
+		},
+		{
+			name:    "Coeff is not a number",
+			raw:     header + "g 1 0 1.0 abc 0.1\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(strings.NewReader(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	embedded, _ := NewCoeffsData()
+	embedded.generation = 0
+	embedded.name = "IGRF"
+	path := filepath.Join(t.TempDir(), "igrf14coeffs.txt")
+	if err := os.WriteFile(path, []byte(igrf14coeffs), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, embedded) {
+		t.Errorf("LoadFile() = %v, want %v", got, embedded)
+	}
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("LoadFile() expected an error for a missing file")
+	}
+}
+
+func TestLoadCustomLayout(t *testing.T) {
+	// unevenly spaced epochs, lines are not ordered, degree 2 appears only in 2010.0
+	raw := `# custom model
+c/s deg ord DGRF DGRF IGRF SV
+g/h n m 2000.0 2003.0 2010.0 2010-12
+h  2  2    0.0   0.0   7.0   0.0
+g  1  0  -10.0 -13.0 -20.0   1.0
+g  1  1    2.0   2.0   4.0   0.0
+h  1  1    3.0   3.0   3.0   0.0
+g  2  0    0.0   0.0   1.0   0.0
+g  2  1    0.0   0.0   2.0   0.0
+h  2  1    0.0   0.0   3.0   0.0
+g  2  2    0.0   0.0   5.0   0.0
+`
+	igrf, err := Load(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := igrf.NMax(); got != 2 {
+		t.Errorf("NMax() = %v, want %v", got, 2)
+	}
+	tests := []struct {
+		name      string
+		date      float64
+		wantG10   float64
+		wantH22   float64
+		wantNmax  int
+		wantErr   bool
+		tolerance float64
+	}{
+		{name: "First epoch", date: 2000.0, wantG10: -10.0, wantH22: 0.0, wantNmax: 1},
+		{name: "Second epoch of uneven interval", date: 2003.0, wantG10: -13.0, wantH22: 0.0, wantNmax: 2},
+		{name: "Middle of the 7-years interval", date: 2006.5, wantG10: -16.5, wantH22: 3.5, wantNmax: 2, tolerance: 0.01},
+		{name: "The last column derived from SV", date: 2012.0, wantG10: -18.0, wantH22: 7.0, wantNmax: 2},
+		{name: "Beyond the last epoch", date: 2012.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, nmax, err := igrf.Coeffs(tt.date)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IGRFcoeffs.Coeffs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if nmax != tt.wantNmax {
+				t.Errorf("IGRFcoeffs.Coeffs() nmax got %v, wanted %v", nmax, tt.wantNmax)
+			}
+			if len(*got) != 8 {
+				t.Fatalf("IGRFcoeffs.Coeffs() got %v coeffs, wanted %v", len(*got), 8)
+			}
+			if math.Abs((*got)[0]-tt.wantG10) > tt.tolerance+1e-9 {
+				t.Errorf("IGRFcoeffs.Coeffs() g(1,0) = %v, want %v", (*got)[0], tt.wantG10)
+			}
+			if math.Abs((*got)[7]-tt.wantH22) > tt.tolerance+1e-9 {
+				t.Errorf("IGRFcoeffs.Coeffs() h(2,2) = %v, want %v", (*got)[7], tt.wantH22)
+			}
+		})
+	}
+}
+
+func TestNewCoeffsDataForGeneration(t *testing.T) {
+	tests := []struct {
+		name      string
+		gen       int
+		wantStart float64
+		wantEnd   float64
+		wantErr   bool
+	}{
+		{name: "IGRF-14", gen: 14, wantStart: 1900.0, wantEnd: 2030.0},
+		{name: "Unknown generation", gen: 99, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCoeffsDataForGeneration(tt.gen)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCoeffsDataForGeneration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Generation() != tt.gen {
+				t.Errorf("Generation() = %v, want %v", got.Generation(), tt.gen)
+			}
+			if want_name := fmt.Sprintf("IGRF-%v", tt.gen); got.Name() != want_name {
+				t.Errorf("Name() = %v, want %v", got.Name(), want_name)
+			}
+			start, end := got.ValidRange()
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ValidRange() = (%v, %v), want (%v, %v)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+	// every listed generation is available, the default one is listed
+	got := Generations()
+	has_default := false
+	for index, generation := range got {
+		if index > 0 && generation <= got[index-1] {
+			t.Errorf("Generations() = %v, want ascending order", got)
+		}
+		if _, err := NewCoeffsDataForGeneration(generation); err != nil {
+			t.Errorf("NewCoeffsDataForGeneration(%v) error = %v", generation, err)
+		}
+		has_default = has_default || generation == DefaultGeneration
+	}
+	if !has_default {
+		t.Errorf("Generations() = %v, want %v included", got, DefaultGeneration)
+	}
+}
+
+func TestIGRFcoeffs_CoeffsSV(t *testing.T) {
+	igrf, _ := NewCoeffsData()
+	tests := []struct {
+		name    string
+		date    float64
+		wantSV  float64
+		wantErr bool
+	}{
+		// the change between epochs spread over days of 5 years, 1904 is a leap year
+		{name: "Between 1900.0 and 1905.0", date: 1902.5, wantSV: (-31464.0 + 31543.0) * 365 / 1826},
+		// 2020 and 2024 are leap years
+		{name: "At the epoch 2020.0", date: 2020.0, wantSV: (-29350.0 + 29403.41) * 366 / 1827},
+		{name: "The SV column", date: 2027.3, wantSV: 12.6 * 5 * 365 / 1826},
+		{name: "The SV column, a leap year", date: 2028.5, wantSV: 12.6 * 5 * 366 / 1826},
+		{name: "The last epoch", date: 2030.0, wantSV: 12.6},
+		{name: "Out of range", date: 2030.1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got_sv, nmax, err := igrf.CoeffsSV(tt.date)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IGRFcoeffs.CoeffsSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			want, _, want_nmax, _ := igrf.Coeffs(tt.date)
+			if nmax != want_nmax || !reflect.DeepEqual(got, want) {
+				t.Errorf("IGRFcoeffs.CoeffsSV() coeffs differ from IGRFcoeffs.Coeffs()")
+			}
+			// g(1,0)
+			if math.Abs((*got_sv)[0]-tt.wantSV) > 0.001 {
+				t.Errorf("IGRFcoeffs.CoeffsSV() SV of g(1,0) = %v, want %v", (*got_sv)[0], tt.wantSV)
+			}
+			// SV is the derivative of interpolated coeffs, including coeffs of degrees that are missing in one of epochs
+			if tt.date < 2030.0 {
+				// coeffs are linear within the year, but not at epochs, so the difference is forward
+				step := 1e-4
+				after, _, _, _ := igrf.Coeffs(tt.date + step)
+				for index := range *got_sv {
+					if derivative := ((*after)[index] - (*got)[index]) / step; math.Abs((*got_sv)[index]-derivative) > 1e-6 {
+						t.Errorf("IGRFcoeffs.CoeffsSV() SV of coeff %v = %v, derivative of coeffs %v", index, (*got_sv)[index], derivative)
+					}
+				}
+			}
+		})
+	}
+}
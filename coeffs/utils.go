@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var comment_line *regexp.Regexp = regexp.MustCompile(`^\s*#.*`)
@@ -29,6 +31,27 @@ func isLeapYear(year int) bool {
 	return isDivisibleBy400 || (isDivisibleBy4 && !isDivisibleBy100)
 }
 
+// DecimalYear converts `t` into decimal year, e.g. 2020-07-02 00:00:00 UTC is 2020.5.
+//
+// The fraction of the year is a fraction of seconds passed since the beginning of the year (UTC)
+// with respect to leap years, the same convention is used to interpolate coeffs between epochs.
+func DecimalYear(t time.Time) float64 {
+	t = t.UTC()
+	year_start := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	fraction_secs := t.Sub(year_start).Seconds()
+	return float64(t.Year()) + fraction_secs/float64(secsInYear(t.Year()))
+}
+
+// TimeFromDecimalYear converts decimal year `date` into UTC time, this is the inverse of `DecimalYear`.
+//
+// The result is rounded to milliseconds, as decimal years near 2000 are precise only to several microseconds.
+func TimeFromDecimalYear(date float64) time.Time {
+	year := math.Floor(date)
+	fraction_secs := (date - year) * float64(secsInYear(int(year)))
+	year_start := time.Date(int(year), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return year_start.Add(time.Duration(math.Round(fraction_secs*1000)) * time.Millisecond)
+}
+
 // Finds the factor for a given `date` between two epochs.
 // In the first approximation the factor is calculated like:
 //
@@ -68,10 +91,36 @@ func findDateFactor(start_epoch, end_epoch string, date float64) (float64, error
 	return factor, nil
 }
 
-// Reads lines from raw coeffs data and writes a srting into a channel, drops comments.
-func coeffsLineProvider() <-chan string {
+// Finds the rate of change of the factor given by `findDateFactor` for a given `date` (1/yr),
+// i.e. its derivative with respect to `date`.
+//
+// Within the year the factor grows linearly with the fraction of seconds, so the rate is
+// the number of seconds in the year of `date` divided by the number of seconds between epochs.
+// Beyond the `end_epoch` the rate is 1 / (end_epoch - start_epoch).
+func findDateFactorRate(start_epoch, end_epoch string, date float64) (float64, error) {
+	parser := errParser{}
+	dte1 := parser.parseFloat(start_epoch)
+	dte2 := parser.parseFloat(end_epoch)
+	if parser.err != nil {
+		return -999, fmt.Errorf("Epoch(s) cannot be parsed, start:%v, end:%v", start_epoch, end_epoch)
+	}
+	if dte2 <= dte1 {
+		return 0, nil
+	}
+	if date > dte2 {
+		return 1 / (dte2 - dte1), nil
+	}
+	var total_secs float64
+	for year := int(dte1); year < int(dte2); year++ {
+		total_secs += float64(secsInYear(year))
+	}
+	return float64(secsInYear(int(date))) / total_secs, nil
+}
+
+// Reads lines from `raw_data` coeffs and writes a srting into a channel, drops comments and empty lines.
+func coeffsLineProvider(raw_data string) <-chan string {
 	ch := make(chan string)
-	coeffs_reader := strings.NewReader(igrf13coeffs)
+	coeffs_reader := strings.NewReader(raw_data)
 	scanner := bufio.NewScanner(coeffs_reader)
 	go func() {
 		defer close(ch)
@@ -80,13 +129,22 @@ func coeffsLineProvider() <-chan string {
 			if comment_line.Match([]byte(line)) {
 				continue
 			}
-			line = strings.Trim(line, " ")
+			line = strings.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
 			ch <- line
 		}
 	}()
 	return ch
 }
 
+// Reads out all remaining lines from the `provider`, so that its goroutine could finish.
+func drainLines(provider <-chan string) {
+	for range provider {
+	}
+}
+
 // epoch2string - converts `epoch` of type `float64` into string.
 func epoch2string(epoch float64) string {
 	return fmt.Sprintf("%.1f", epoch)
@@ -100,15 +158,47 @@ func parseArrayToFloat(raw_data []string) (*[]float64, error) {
 		if err != nil {
 			return nil, errors.New("unable to parse coeffs")
 		}
-		if index == len(raw_data)-1 {
-			// real value calculated for the SV column
-			real_data = data[index-1] + real_data*interval
-		}
 		data[index] = real_data
 	}
 	return &data, nil
 }
 
+// Returns the degree of the coeff given by `g/h n m` columns and its index in the set of coeffs.
+//
+// Coeffs are ordered as g(1,0), g(1,1), h(1,1), g(2,0), g(2,1), h(2,1), g(2,2), h(2,2), ...
+func parseCoeffIndex(gh, raw_n, raw_m string) (int, int, error) {
+	n, err := strconv.Atoi(raw_n)
+	if err != nil {
+		return 0, 0, fmt.Errorf("degree %v is incorrect", raw_n)
+	}
+	m, err := strconv.Atoi(raw_m)
+	if err != nil {
+		return 0, 0, fmt.Errorf("order %v is incorrect", raw_m)
+	}
+	if n < 1 || m < 0 || m > n {
+		return 0, 0, fmt.Errorf("degree %v and order %v are incorrect", n, m)
+	}
+	// number of coeffs for all degrees less than `n`
+	index := n*n - 1
+	switch {
+	case gh == "g" && m == 0:
+	case gh == "g":
+		index += 2*m - 1
+	case gh == "h" && m > 0:
+		index += 2 * m
+	default:
+		return 0, 0, fmt.Errorf("coeff %v(%v,%v) is incorrect", gh, n, m)
+	}
+	return n, index, nil
+}
+
+// Extends `coeffs` with zeros up to `size` elements.
+func resizeCoeffs(coeffs *[]float64, size int) {
+	for len(*coeffs) < size {
+		*coeffs = append(*coeffs, 0)
+	}
+}
+
 type errParser struct {
 	err error
 }
@@ -1,7 +1,9 @@
 package coeffs
 
 import (
+	"math"
 	"testing"
+	"time"
 )
 
 const secs_per_leap_year = 31622400
@@ -157,3 +159,60 @@ func Test_findDateFactor(t *testing.T) {
 		})
 	}
 }
+
+func TestDecimalYear(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want float64
+	}{
+		{
+			name: "Beginning of the year",
+			time: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+			want: 2021.0,
+		},
+		{
+			name: "Middle of a regular year",
+			time: time.Date(2021, time.July, 2, 12, 0, 0, 0, time.UTC),
+			want: 2021.5,
+		},
+		{
+			name: "Middle of a leap year",
+			time: time.Date(2020, time.July, 2, 0, 0, 0, 0, time.UTC),
+			want: 2020.5,
+		},
+		{
+			name: "The last day of a leap year",
+			time: time.Date(2020, time.December, 31, 12, 0, 0, 0, time.UTC),
+			want: 2020 + 365.5/366,
+		},
+		{
+			name: "Time zones are respected",
+			time: time.Date(2021, time.January, 1, 3, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)),
+			want: 2021.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecimalYear(tt.time)
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("DecimalYear() = %v, want %v", got, tt.want)
+			}
+			if back := TimeFromDecimalYear(got); !back.Equal(tt.time) {
+				t.Errorf("TimeFromDecimalYear() = %v, want %v", back, tt.time)
+			}
+		})
+	}
+}
+
+func TestDecimalYearInterpolation(t *testing.T) {
+	// the factor of a decimal year is the fraction of seconds between epochs
+	moment := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	want := moment.Sub(start).Seconds() / end.Sub(start).Seconds()
+	got, _ := findDateFactor("2020.0", "2025.0", DecimalYear(moment))
+	if math.Abs(got-want) > 1e-12 {
+		t.Errorf("findDateFactor() = %v, want %v", got, want)
+	}
+}
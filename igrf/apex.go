@@ -0,0 +1,298 @@
+package igrf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// the mean Earth radius of Modified Apex and Quasi-Dipole coordinates, km
+	apex_earths_radius = 6371.009
+	// tracing parameters of field lines to apexes, the position error per step in km
+	// and the maximal step relative to the distance from the Earth's centre
+	apex_tolerance      = 1e-7
+	apex_max_step_ratio = 0.01
+	// the distance for numerical gradients of apex altitude and longitude, km
+	apex_gradient_step = 1.0
+	// the inverse transform stops when coordinates differ less than this value, decimal degrees
+	apex_inverse_tolerance      = 1e-7
+	apex_inverse_max_iterations = 30
+	// the maximal change of the dipole latitude or longitude in a single iteration of the inverse transform
+	apex_inverse_max_step = 5.0
+)
+
+// ApexResults represents Modified Apex and Quasi-Dipole coordinates of a point, see Richmond (1995)
+// Ionospheric electrodynamics using magnetic apex coordinates and Emmert et al. (2010) A computationally compact
+// representation of Magnetic-Apex and Quasi-Dipole coordinates with smooth base vectors.
+//
+// QuasiDipoleLat - Quasi-Dipole latitude in decimal degrees.
+//
+// ApexLat - Modified Apex latitude for the reference altitude in decimal degrees,
+// it's NaN if the apex is below the reference altitude.
+//
+// ApexLon - apex longitude, the centred dipole longitude of the apex, the same for both coordinate systems.
+//
+// ApexAlt - geodetic altitude of the apex of the field line through the point in km.
+type ApexResults struct {
+	QuasiDipoleLat float64
+	ApexLat        float64
+	ApexLon        float64
+	ApexAlt        float64
+}
+
+// ApexBaseVectors represents base vectors of Quasi-Dipole (F1, F2) and Modified Apex (D1, D2, D3, E1, E2, E3)
+// coordinates at a point, see Richmond (1995). Vectors are in the local geodetic frame: north, east, down.
+//
+// F - (F1 x F2) projected to the upward unit vector, D - the length of D1 x D2.
+//
+// Modified Apex vectors and D are NaN if the apex is below the reference altitude.
+type ApexBaseVectors struct {
+	F1, F2     [3]float64
+	D1, D2, D3 [3]float64
+	E1, E2, E3 [3]float64
+	F, D       float64
+}
+
+// GeodeticToApex computes Modified Apex coordinates for the reference altitude `ref_alt` (km, usually 110.0)
+// and Quasi-Dipole coordinates of the geodetic point `lat`, `lon`, `alt` (km, not less than -1.0)
+// for the decimal `date`. The apex is found by tracing the field line, see `TraceFieldLine`.
+func (igd *IGRFdata) GeodeticToApex(lat, lon, alt, date, ref_alt float64) (ApexResults, error) {
+	point := Point{Lat: lat, Lon: lon, Alt: alt}
+	if err := checkApexConditions(point, ref_alt); err != nil {
+		return ApexResults{}, err
+	}
+	at, err := igd.newApexTransform(date)
+	if err != nil {
+		return ApexResults{}, err
+	}
+	apex_alt, apex_lon, north := at.apexOf(point)
+	return ApexResults{
+		QuasiDipoleLat: apexLatitude(alt, apex_alt, north),
+		ApexLat:        apexLatitude(ref_alt, apex_alt, north),
+		ApexLon:        apex_lon,
+		ApexAlt:        apex_alt,
+	}, nil
+}
+
+// QuasiDipoleToGeodetic computes the geodetic point at the altitude `alt` (km) with Quasi-Dipole latitude `qdlat`
+// and longitude `qdlon` (decimal degrees) for the decimal `date`, it's the inverse of `GeodeticToApex`.
+func (igd *IGRFdata) QuasiDipoleToGeodetic(qdlat, qdlon, alt, date float64) (Point, error) {
+	if !(math.Abs(qdlat) < 90) {
+		return Point{}, fmt.Errorf("Quasi-Dipole latitude %v° is out of range (-90.0, 90.0)", qdlat)
+	}
+	if err := checkApexConditions(Point{Lat: 0, Lon: qdlon, Alt: alt}, 0); err != nil {
+		return Point{}, err
+	}
+	at, err := igd.newApexTransform(date)
+	if err != nil {
+		return Point{}, err
+	}
+	return at.inverse(qdlat, qdlon, alt)
+}
+
+// ApexToGeodetic computes the geodetic point at the altitude `alt` (km) with Modified Apex latitude `alat`
+// and longitude `alon` (decimal degrees) for the reference altitude `ref_alt` and the decimal `date`,
+// it's the inverse of `GeodeticToApex`. The point must not be above the apex of its field line.
+func (igd *IGRFdata) ApexToGeodetic(alat, alon, alt, date, ref_alt float64) (Point, error) {
+	if !(math.Abs(alat) < 90) {
+		return Point{}, fmt.Errorf("Apex latitude %v° is out of range (-90.0, 90.0)", alat)
+	}
+	if err := checkApexConditions(Point{Lat: 0, Lon: alon, Alt: alt}, ref_alt); err != nil {
+		return Point{}, err
+	}
+	// both latitudes are defined by the apex altitude
+	cos_alat := math.Cos(alat * math.Pi / 180)
+	cos2 := cos_alat * cos_alat * (apex_earths_radius + alt) / (apex_earths_radius + ref_alt)
+	if cos2 > 1 {
+		return Point{}, fmt.Errorf("the altitude %v km is above the apex of the field line", alt)
+	}
+	qdlat := math.Copysign(rad2deg(math.Acos(math.Sqrt(cos2))), alat)
+	return igd.QuasiDipoleToGeodetic(qdlat, alon, alt, date)
+}
+
+// ApexBaseVectors computes base vectors of Quasi-Dipole and Modified Apex coordinates for the reference altitude
+// `ref_alt` at the geodetic point `lat`, `lon`, `alt` for the decimal `date`, see `GeodeticToApex`.
+// Gradients of apex coordinates are computed numerically, so vectors are inaccurate very close to the dip equator.
+func (igd *IGRFdata) ApexBaseVectors(lat, lon, alt, date, ref_alt float64) (ApexBaseVectors, error) {
+	point := Point{Lat: lat, Lon: lon, Alt: alt}
+	if err := checkApexConditions(point, ref_alt); err != nil {
+		return ApexBaseVectors{}, err
+	}
+	at, err := igd.newApexTransform(date)
+	if err != nil {
+		return ApexBaseVectors{}, err
+	}
+	apex_alt, _, north := at.apexOf(point)
+	qdlat := apexLatitude(alt, apex_alt, north) * math.Pi / 180
+	// gradients per km in the local geodetic frame
+	var grad_apex_alt, grad_qdlat, grad_lon [3]float64
+	for axis := 0; axis < 3; axis++ {
+		var alts, lats, lons [2]float64
+		for i, sign := range []float64{-1, 1} {
+			shifted := shiftPoint(point, axis, sign*apex_gradient_step)
+			shifted_alt, shifted_lon, shifted_north := at.apexOf(shifted)
+			alts[i] = shifted_alt
+			lats[i] = apexLatitude(shifted.Alt, shifted_alt, shifted_north) * math.Pi / 180
+			lons[i] = shifted_lon * math.Pi / 180
+		}
+		grad_apex_alt[axis] = (alts[1] - alts[0]) / (2 * apex_gradient_step)
+		grad_qdlat[axis] = (lats[1] - lats[0]) / (2 * apex_gradient_step)
+		grad_lon[axis] = normalizeLongitude(rad2deg(lons[1]-lons[0])) * math.Pi / 180 / (2 * apex_gradient_step)
+	}
+	up := [3]float64{0, 0, -1}
+	var vectors ApexBaseVectors
+	// Quasi-Dipole: f1 = Re * grad(qdlat) x k, f2 = k x (Re * cos(qdlat) * grad(lon))
+	vectors.F1 = cross(scale(grad_qdlat, apex_earths_radius), up)
+	vectors.F2 = cross(up, scale(grad_lon, apex_earths_radius*math.Cos(qdlat)))
+	vectors.F = dot(cross(vectors.F1, vectors.F2), up)
+	// Modified Apex: d1 = R * cos(alat) * grad(lon), d2 = -R * grad(alat) / sin(Im), d3 = d1 x d2 / D^2,
+	// e1 = d2 x d3, e2 = d3 x d1, e3 = d1 x d2, where R = Re + ref_alt and sin(Im) = 2 * sin(alat) / sqrt(4 - 3 * cos^2(alat))
+	alat := apexLatitude(ref_alt, apex_alt, north) * math.Pi / 180
+	radius := apex_earths_radius + ref_alt
+	sin_alat, cos_alat := math.Sincos(alat)
+	// the gradient of the apex latitude from cos^2(alat) = R / (Re + apex_alt)
+	grad_alat := scale(grad_apex_alt, radius/(2*sin_alat*cos_alat*math.Pow(apex_earths_radius+apex_alt, 2)))
+	sin_im := 2 * sin_alat / math.Sqrt(4-3*cos_alat*cos_alat)
+	vectors.D1 = scale(grad_lon, radius*cos_alat)
+	vectors.D2 = scale(grad_alat, -radius/sin_im)
+	vectors.E3 = cross(vectors.D1, vectors.D2)
+	vectors.D = math.Sqrt(dot(vectors.E3, vectors.E3))
+	vectors.D3 = scale(vectors.E3, 1/(vectors.D*vectors.D))
+	vectors.E1 = cross(vectors.D2, vectors.D3)
+	vectors.E2 = cross(vectors.D3, vectors.D1)
+	return vectors, nil
+}
+
+// finds apexes of field lines for a fixed date
+type apexTransform struct {
+	tr *tracer
+	dp centredDipole
+}
+
+// Returns the apex transform for the `date`.
+func (igd *IGRFdata) newApexTransform(date float64) (*apexTransform, error) {
+	dp, err := igd.dipole(date)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := igd.newTracer(date, TraceOptions{TargetAlt: -1, Tolerance: apex_tolerance})
+	if err != nil {
+		return nil, err
+	}
+	tr.max_step_ratio = apex_max_step_ratio
+	return &apexTransform{tr: tr, dp: dp}, nil
+}
+
+// Returns the altitude (km) and the centred dipole longitude (decimal degrees) of the apex of the field line
+// through the `point` and whether the point is in the northern magnetic hemisphere, where the field goes down.
+func (at *apexTransform) apexOf(point Point) (float64, float64, bool) {
+	north := at.tr.northern(point)
+	// trace upwards, the apex search needs points ordered along the field
+	sign := 1.0
+	if north {
+		sign = -1.0
+	}
+	points, _ := at.tr.trace(toECEF(point), sign)
+	if north {
+		reversePoints(points)
+	}
+	apex := at.tr.apex(points)
+	position := toECEF(apex)
+	_, apex_lon := at.dp.rotate(rad2deg(math.Asin(position[2]/magnitude(position))), apex.Lon, false)
+	// the search could slightly underestimate the altitude if the point is the apex itself
+	return math.Max(apex.Alt, point.Alt), apex_lon, north
+}
+
+// Finds the geodetic point at the altitude `alt` with Quasi-Dipole coordinates `qdlat`, `qdlon` by Newton iterations
+// over centred dipole coordinates of the point, the Jacobian is computed numerically.
+func (at *apexTransform) inverse(qdlat, qdlon, alt float64) (Point, error) {
+	residual := func(mlat, mlon float64) (Point, float64, float64) {
+		lat, lon := at.dp.rotate(mlat, mlon, true)
+		point := Point{Lat: lat, Lon: lon, Alt: alt}
+		apex_alt, apex_lon, north := at.apexOf(point)
+		return point, apexLatitude(alt, apex_alt, north) - qdlat, normalizeLongitude(apex_lon - qdlon)
+	}
+	mlat, mlon := qdlat, qdlon
+	const step = 1e-3
+	for i := 0; i < apex_inverse_max_iterations; i++ {
+		point, dlat, dlon := residual(mlat, mlon)
+		if math.Abs(dlat) < apex_inverse_tolerance && math.Abs(dlon) < apex_inverse_tolerance {
+			return point, nil
+		}
+		_, dlat_lat, dlon_lat := residual(mlat+step, mlon)
+		_, dlat_lon, dlon_lon := residual(mlat, mlon+step)
+		j11, j12 := (dlat_lat-dlat)/step, (dlat_lon-dlat)/step
+		j21, j22 := normalizeLongitude(dlon_lat-dlon)/step, normalizeLongitude(dlon_lon-dlon)/step
+		det := j11*j22 - j12*j21
+		if det == 0 {
+			return Point{}, errors.New("the Jacobian of apex coordinates is degenerate")
+		}
+		delta_lat := -(dlat*j22 - dlon*j12) / det
+		delta_lon := -(j11*dlon - j21*dlat) / det
+		if norm := math.Hypot(delta_lat, delta_lon); norm > apex_inverse_max_step {
+			delta_lat *= apex_inverse_max_step / norm
+			delta_lon *= apex_inverse_max_step / norm
+		}
+		mlat = math.Max(-89.9, math.Min(89.9, mlat+delta_lat))
+		mlon = normalizeLongitude(mlon + delta_lon)
+	}
+	return Point{}, fmt.Errorf("no convergence after %v iterations", apex_inverse_max_iterations)
+}
+
+// Returns the apex latitude (decimal degrees) of the point at the altitude `alt` on the field line
+// with the apex at `apex_alt`: cos^2(lat) = (Re + alt) / (Re + apex_alt), NaN if the apex is below the point.
+func apexLatitude(alt, apex_alt float64, north bool) float64 {
+	if apex_alt < alt {
+		return math.NaN()
+	}
+	lat := rad2deg(math.Acos(math.Sqrt((apex_earths_radius + alt) / (apex_earths_radius + apex_alt))))
+	if north {
+		return lat
+	}
+	return -lat
+}
+
+// Returns the geodetic point moved by the `distance` (km) along the `axis` of the local frame: north, east or down.
+func shiftPoint(point Point, axis int, distance float64) Point {
+	slat, clat := math.Sincos(point.Lat * math.Pi / 180)
+	e2 := wgs84_f * (2 - wgs84_f)
+	w := math.Sqrt(1 - e2*slat*slat)
+	// meridian and prime vertical radii of curvature, km
+	meridian := wgs84_a / 1000.0 * (1 - e2) / (w * w * w)
+	prime := wgs84_a / 1000.0 / w
+	switch axis {
+	case 0:
+		point.Lat += rad2deg(distance / (meridian + point.Alt))
+	case 1:
+		point.Lon = normalizeLongitude(point.Lon + rad2deg(distance/((prime+point.Alt)*clat)))
+	default:
+		point.Alt -= distance
+	}
+	return point
+}
+
+// Returns the cross product of vectors `a` and `b`.
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+// Returns the dot product of vectors `a` and `b`.
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// Returns the vector `v` multiplied by `factor`.
+func scale(v [3]float64, factor float64) [3]float64 {
+	return [3]float64{v[0] * factor, v[1] * factor, v[2] * factor}
+}
+
+func checkApexConditions(point Point, ref_alt float64) error {
+	if err := checkTraceConditions(point, TraceOptions{TargetAlt: -1}); err != nil {
+		return err
+	}
+	if ref_alt < 0 {
+		return fmt.Errorf("reference altitude %v km must not be negative", ref_alt)
+	}
+	return nil
+}
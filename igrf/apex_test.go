@@ -0,0 +1,211 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_GeodeticToApex(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		point   Point
+		ref_alt float64
+		wantErr bool
+	}{
+		{name: "Northern hemisphere", point: Point{60, 30, 0}, ref_alt: 110},
+		{name: "Southern hemisphere", point: Point{-70, 20, 0}, ref_alt: 110},
+		{name: "Above the reference altitude", point: Point{45, -100, 300}, ref_alt: 110},
+		{name: "Low latitude", point: Point{10, -75, 0}, ref_alt: 110},
+		{name: "Negative reference altitude", point: Point{60, 30, 0}, ref_alt: -10, wantErr: true},
+		{name: "Below the limit", point: Point{60, 30, -2}, ref_alt: 110, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.GeodeticToApex(tt.point.Lat, tt.point.Lon, tt.point.Alt, 2020.0, tt.ref_alt)
+			if !checkError(t, "GeodeticToApex", err, tt.wantErr) {
+				return
+			}
+			// both latitudes are defined by the apex altitude
+			cos_qdlat := math.Cos(got.QuasiDipoleLat * math.Pi / 180)
+			cos_alat := math.Cos(got.ApexLat * math.Pi / 180)
+			want := (apex_earths_radius + tt.ref_alt) / (apex_earths_radius + tt.point.Alt)
+			if !isClose(cos_alat*cos_alat/(cos_qdlat*cos_qdlat), want, 1e-9, 0) || got.QuasiDipoleLat*got.ApexLat < 0 {
+				t.Errorf("GeodeticToApex() = %v, inconsistent latitudes", got)
+			}
+			// the conjugate point is on the same field line
+			conjugate, err := igrf_data.ConjugatePoint(tt.point, 2020.0)
+			if err != nil {
+				t.Fatalf("ConjugatePoint() error = %v", err)
+			}
+			// both trace the field line to the same apex
+			if !isClose(conjugate.Apex.Alt, got.ApexAlt, 1e-5, 0.01) {
+				t.Errorf("ConjugatePoint() apex altitude = %v, want %v", conjugate.Apex.Alt, got.ApexAlt)
+			}
+			other, err := igrf_data.GeodeticToApex(conjugate.Conjugate.Lat, conjugate.Conjugate.Lon, conjugate.Conjugate.Alt, 2020.0, tt.ref_alt)
+			if err != nil {
+				t.Fatalf("GeodeticToApex() error = %v", err)
+			}
+			if !isClose(other.ApexAlt, got.ApexAlt, 1e-5, 0.01) || math.Abs(other.ApexLon-got.ApexLon) > 1e-3 ||
+				math.Abs(other.QuasiDipoleLat+got.QuasiDipoleLat) > 1e-3 {
+				t.Errorf("GeodeticToApex() = %v, at the conjugate point %v", got, other)
+			}
+			// the inverse transforms
+			for _, inverse := range []struct {
+				name string
+				f    func() (Point, error)
+			}{
+				{"QuasiDipoleToGeodetic", func() (Point, error) {
+					return igrf_data.QuasiDipoleToGeodetic(got.QuasiDipoleLat, got.ApexLon, tt.point.Alt, 2020.0)
+				}},
+				{"ApexToGeodetic", func() (Point, error) {
+					return igrf_data.ApexToGeodetic(got.ApexLat, got.ApexLon, tt.point.Alt, 2020.0, tt.ref_alt)
+				}},
+			} {
+				point, err := inverse.f()
+				if err != nil {
+					t.Fatalf("%v() error = %v", inverse.name, err)
+				}
+				if math.Abs(point.Lat-tt.point.Lat) > 1e-6 || math.Abs(point.Lon-tt.point.Lon) > 1e-6 {
+					t.Errorf("%v() = %v, want %v", inverse.name, point, tt.point)
+				}
+			}
+		})
+	}
+}
+
+func TestIGRFdata_GeodeticToApexReference(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name     string
+		point    Point
+		date     float64
+		ref_alt  float64
+		wantALat float64
+		wantALon float64
+	}{
+		// the example of apexpy (Emmert et al., 2010): Apex(date=2015.3).convert(60, 15, 'geo', 'apex', height=300)
+		{name: "apexpy", point: Point{60, 15, 300}, date: 2015.3, ref_alt: 0, wantALat: 57.47145462036133, wantALon: 93.62657165527344},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.GeodeticToApex(tt.point.Lat, tt.point.Lon, tt.point.Alt, tt.date, tt.ref_alt)
+			if err != nil {
+				t.Fatalf("GeodeticToApex() error = %v", err)
+			}
+			// apexpy interpolates precomputed grids and uses coeffs of an earlier IGRF generation
+			if math.Abs(got.ApexLat-tt.wantALat) > 0.05 || math.Abs(got.ApexLon-tt.wantALon) > 0.05 {
+				t.Errorf("GeodeticToApex() = %v, want apex latitude %v, longitude %v", got, tt.wantALat, tt.wantALon)
+			}
+		})
+	}
+}
+
+func TestIGRFdata_GeodeticToApexDipole(t *testing.T) {
+	// for the axial dipole Quasi-Dipole coordinates are close to geocentric ones
+	dipole := newAxialDipole(t)
+	for _, point := range []Point{{60, 30, 0}, {-45, -100, 300}, {20, 170, 0}} {
+		got, err := dipole.GeodeticToApex(point.Lat, point.Lon, point.Alt, 2001.0, 110)
+		if err != nil {
+			t.Fatalf("GeodeticToApex() error = %v", err)
+		}
+		position := toECEF(point)
+		lat := rad2deg(math.Asin(position[2] / magnitude(position)))
+		if math.Abs(got.QuasiDipoleLat-lat) > 0.1 || math.Abs(got.ApexLon-point.Lon) > 1e-6 {
+			t.Errorf("GeodeticToApex(%v) = %v, want close to %v, %v", point, got, lat, point.Lon)
+		}
+	}
+	// the point at the apex
+	got, _ := dipole.GeodeticToApex(0, 100, 300, 2001.0, 110)
+	if math.Abs(got.QuasiDipoleLat) > 1e-6 || math.Abs(got.ApexAlt-300) > 1e-6 {
+		t.Errorf("GeodeticToApex() = %v, want the apex at the point", got)
+	}
+}
+
+func TestIGRFdata_ApexToGeodetic(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		lat     float64
+		alt     float64
+		wantErr bool
+	}{
+		{name: "Latitude out of range", lat: 90, alt: 0, wantErr: true},
+		{name: "Above the apex", lat: 1, alt: 1000, wantErr: true},
+		{name: "Valid", lat: 50, alt: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := igrf_data.ApexToGeodetic(tt.lat, 30, tt.alt, 2020.0, 110)
+			checkError(t, "ApexToGeodetic", err, tt.wantErr)
+		})
+	}
+}
+
+func TestIGRFdata_ApexBaseVectors(t *testing.T) {
+	igrf_data := New()
+	dipole := newAxialDipole(t)
+	for _, point := range []Point{{60, 30, 0}, {-70, 20, 0}, {45, -100, 300}} {
+		got, err := igrf_data.ApexBaseVectors(point.Lat, point.Lon, point.Alt, 2020.0, 110)
+		if err != nil {
+			t.Fatalf("ApexBaseVectors() error = %v", err)
+		}
+		// d and e vectors are reciprocal
+		d := [3][3]float64{got.D1, got.D2, got.D3}
+		e := [3][3]float64{got.E1, got.E2, got.E3}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				want := 0.0
+				if i == j {
+					want = 1.0
+				}
+				if math.Abs(dot(d[i], e[j])-want) > 1e-9 {
+					t.Errorf("ApexBaseVectors(%v) d%v * e%v = %v, want %v", point, i+1, j+1, dot(d[i], e[j]), want)
+				}
+			}
+		}
+		// e3 is along the field, d1 and d2 are perpendicular to it
+		res, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, 2020.0)
+		b := [3]float64{res.NorthComponent, res.EastComponent, res.VerticalComponent}
+		b = scale(b, 1/res.TotalIntensity)
+		if cosine := dot(got.E3, b) / math.Sqrt(dot(got.E3, got.E3)); cosine < 0.9999 {
+			t.Errorf("ApexBaseVectors(%v) e3 = %v, want along the field %v", point, got.E3, b)
+		}
+		if math.Abs(dot(got.D1, b)) > 1e-3 || math.Abs(dot(got.D2, b)) > 1e-3 {
+			t.Errorf("ApexBaseVectors(%v) d1 = %v, d2 = %v, want perpendicular to %v", point, got.D1, got.D2, b)
+		}
+		// f vectors are horizontal
+		if got.F1[2] != 0 || got.F2[2] != 0 || math.Abs(got.F-1) > 0.5 {
+			t.Errorf("ApexBaseVectors(%v) f1 = %v, f2 = %v, F = %v", point, got.F1, got.F2, got.F)
+		}
+		// for the axial dipole f1 and f2 are close to eastward and northward unit vectors
+		got, err = dipole.ApexBaseVectors(point.Lat, point.Lon, point.Alt, 2001.0, 110)
+		if err != nil {
+			t.Fatalf("ApexBaseVectors() error = %v", err)
+		}
+		east := got.F1[1] / math.Sqrt(dot(got.F1, got.F1))
+		north := got.F2[0] / math.Sqrt(dot(got.F2, got.F2))
+		if east < 0.9999 || north < 0.9999 || math.Abs(got.F-1) > 0.1 {
+			t.Errorf("ApexBaseVectors(%v) for the dipole f1 = %v, f2 = %v, F = %v", point, got.F1, got.F2, got.F)
+		}
+	}
+}
+
+func TestTracerApex(t *testing.T) {
+	// the apex of an axial dipole field line is above the equator at the distance r / cos^2(lat)
+	dipole := newAxialDipole(t)
+	tr, err := dipole.newTracer(2001.0, TraceOptions{})
+	if err != nil {
+		t.Fatalf("newTracer() error = %v", err)
+	}
+	for _, point := range []Point{{30, 10, 0}, {-45, -60, 500}, {5, 170, 100}} {
+		position := toECEF(point)
+		radius := magnitude(position)
+		cos_lat := math.Hypot(position[0], position[1]) / radius
+		want := radius/(cos_lat*cos_lat) - wgs84_a/1000.0
+		got := tr.apex(tr.fieldLine(point).Points)
+		if math.Abs(got.Alt-want) > 0.01 || math.Abs(got.Lat) > 1e-4 || math.Abs(got.Lon-point.Lon) > 1e-6 {
+			t.Errorf("apex() of the line through %v = %v, want altitude %v above the equator", point, got, want)
+		}
+	}
+}
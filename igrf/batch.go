@@ -0,0 +1,59 @@
+package igrf
+
+import (
+	"errors"
+
+	"github.com/proway2/go-igrf/calc"
+)
+
+// Query is a single set of inputs for `IGRFBatch`, see `IGRF` for valid values.
+type Query struct {
+	Lat  float64
+	Lon  float64
+	Alt  float64
+	Date float64
+}
+
+// IGRFBatch computes values for the geomagnetic field and secular variation for every query in `queries`.
+//
+// Results and errors are in the same order as queries, the error is nil for a successfully computed query.
+// Coeffs are interpolated again only when the date differs from the date of the previous query, so queries
+// should be grouped by date, and spherical harmonic sums reuse the same buffers, so that processing a batch of points
+// doesn't allocate memory per point.
+func (igd *IGRFdata) IGRFBatch(queries []Query) ([]IGRFresults, []error) {
+	results := make([]IGRFresults, len(queries))
+	errs := make([]error, len(queries))
+	if igd.shc == nil {
+		err := errors.New("IGRFdata structure is not initialized")
+		for index := range errs {
+			errs[index] = err
+		}
+		return results, errs
+	}
+	// coeffs for the date of the previous query
+	var (
+		date         float64
+		set          coeffsSet
+		set_err      error
+		interpolated bool
+	)
+	ws := calc.NewWorkspace(igd.shc.NMax())
+	for index, query := range queries {
+		if err := checkInitialConditions(query.Lat, query.Lon, query.Alt); err != nil {
+			errs[index] = err
+			continue
+		}
+		if !interpolated || query.Date != date {
+			date, interpolated = query.Date, true
+			set, set_err = igd.coeffsSet(date)
+		}
+		if set_err != nil {
+			errs[index] = set_err
+			continue
+		}
+		ws.SetLatitude(query.Lat, query.Alt, set.nmax)
+		ws.SetLongitude(query.Lon, set.nmax)
+		results[index] = set.results(ws)
+	}
+	return results, errs
+}
@@ -0,0 +1,79 @@
+package igrf
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestIGRFdata_IGRFBatch(t *testing.T) {
+	igrf_data := New()
+	queries := []Query{
+		{Lat: 59.9, Lon: 39.9, Alt: 0.0, Date: 2021.5},
+		{Lat: -64.081, Lon: 135.866, Alt: 0.0, Date: 2021.5},
+		{Lat: 46.9, Lon: 39.9, Alt: 1.1, Date: 1955.3},
+		{Lat: 91.0, Lon: 39.9, Alt: 0.0, Date: 2021.5},
+		{Lat: 59.9, Lon: 39.9, Alt: 0.0, Date: 1800.0},
+		{Lat: 89.9999, Lon: -120.0, Alt: 100.0, Date: 2029.5},
+	}
+	got, errs := igrf_data.IGRFBatch(queries)
+	if len(got) != len(queries) || len(errs) != len(queries) {
+		t.Fatalf("IGRFBatch() returned %v results and %v errors, want %v", len(got), len(errs), len(queries))
+	}
+	for index, query := range queries {
+		want, want_err := igrf_data.IGRF(query.Lat, query.Lon, query.Alt, query.Date)
+		if (errs[index] != nil) != (want_err != nil) {
+			t.Errorf("IGRFBatch() query %v error = %v, want %v", index, errs[index], want_err)
+			continue
+		}
+		if got[index] != want {
+			t.Errorf("IGRFBatch() query %v = %v, want %v", index, got[index], want)
+		}
+	}
+	if _, errs := (&IGRFdata{}).IGRFBatch(queries[:1]); errs[0] == nil {
+		t.Errorf("IGRFBatch() expected an error for not initialized structure")
+	}
+}
+
+func TestIGRFBatchAllocations(t *testing.T) {
+	igrf_data := New()
+	queries := make([]Query, 1000)
+	distinct := make([]Query, len(queries))
+	for index := range queries {
+		queries[index] = Query{Lat: float64(index%180) - 89.5, Lon: float64(index%360) - 179.5, Alt: 10.0, Date: 2020.5}
+		distinct[index] = queries[index]
+		distinct[index].Date = 2000.0 + float64(index)*0.01
+	}
+	small := testing.AllocsPerRun(5, func() { igrf_data.IGRFBatch(queries[:10]) })
+	large := testing.AllocsPerRun(5, func() { igrf_data.IGRFBatch(queries) })
+	// allocations depend on the number of distinct dates, not on the number of points
+	if large != small {
+		t.Errorf("IGRFBatch() allocations for 1000 points = %v, for 10 points = %v", large, small)
+	}
+	// every distinct date costs only the interpolation of coeffs, nothing is kept for previous dates
+	interpolate := func() {
+		for _, query := range distinct {
+			igrf_data.coeffsSet(query.Date)
+		}
+	}
+	batch := func() { igrf_data.IGRFBatch(distinct) }
+	if got, want := testing.AllocsPerRun(5, batch), large+testing.AllocsPerRun(5, interpolate); got > want {
+		t.Errorf("IGRFBatch() allocations for 1000 dates = %v, want at most %v", got, want)
+	}
+	large_bytes := bytesPerRun(5, func() { igrf_data.IGRFBatch(queries) })
+	if got, want := bytesPerRun(5, batch), large_bytes+bytesPerRun(5, interpolate); got > want {
+		t.Errorf("IGRFBatch() allocated %v bytes for 1000 dates, want at most %v", got, want)
+	}
+}
+
+// Returns the average number of bytes allocated by `f` in `runs` calls.
+func bytesPerRun(runs int, f func()) uint64 {
+	// a warm-up call as in testing.AllocsPerRun
+	f()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < runs; i++ {
+		f()
+	}
+	runtime.ReadMemStats(&after)
+	return (after.TotalAlloc - before.TotalAlloc) / uint64(runs)
+}
@@ -0,0 +1,102 @@
+package igrf
+
+import "fmt"
+
+// Component is a single value of `IGRFresults`, e.g. for contours of the field.
+type Component int
+
+const (
+	Declination Component = iota
+	DeclinationSV
+	Inclination
+	InclinationSV
+	HorizontalIntensity
+	HorizontalSV
+	NorthComponent
+	NorthSV
+	EastComponent
+	EastSV
+	VerticalComponent
+	VerticalSV
+	TotalIntensity
+	TotalSV
+)
+
+var component_names = [...]string{
+	"Declination",
+	"DeclinationSV",
+	"Inclination",
+	"InclinationSV",
+	"HorizontalIntensity",
+	"HorizontalSV",
+	"NorthComponent",
+	"NorthSV",
+	"EastComponent",
+	"EastSV",
+	"VerticalComponent",
+	"VerticalSV",
+	"TotalIntensity",
+	"TotalSV",
+}
+
+// String returns the name of the component, the same as the name of the field of `IGRFresults`.
+func (c Component) String() string {
+	if !c.valid() {
+		return fmt.Sprintf("Component(%d)", int(c))
+	}
+	return component_names[c]
+}
+
+// Value returns the value of the component from `res`.
+func (c Component) Value(res IGRFresults) float64 {
+	switch c {
+	case Declination:
+		return res.Declination
+	case DeclinationSV:
+		return res.DeclinationSV
+	case Inclination:
+		return res.Inclination
+	case InclinationSV:
+		return res.InclinationSV
+	case HorizontalIntensity:
+		return res.HorizontalIntensity
+	case HorizontalSV:
+		return res.HorizontalSV
+	case NorthComponent:
+		return res.NorthComponent
+	case NorthSV:
+		return res.NorthSV
+	case EastComponent:
+		return res.EastComponent
+	case EastSV:
+		return res.EastSV
+	case VerticalComponent:
+		return res.VerticalComponent
+	case VerticalSV:
+		return res.VerticalSV
+	case TotalIntensity:
+		return res.TotalIntensity
+	case TotalSV:
+		return res.TotalSV
+	}
+	return 0
+}
+
+// Unit returns the unit of the component: deg, arcmin/yr, nT or nT/yr.
+func (c Component) Unit() string {
+	switch c {
+	case Declination, Inclination:
+		return "deg"
+	case DeclinationSV, InclinationSV:
+		return "arcmin/yr"
+	case HorizontalIntensity, NorthComponent, EastComponent, VerticalComponent, TotalIntensity:
+		return "nT"
+	case HorizontalSV, NorthSV, EastSV, VerticalSV, TotalSV:
+		return "nT/yr"
+	}
+	return ""
+}
+
+func (c Component) valid() bool {
+	return c >= Declination && c <= TotalSV
+}
@@ -0,0 +1,34 @@
+package igrf
+
+import "testing"
+
+func TestComponent(t *testing.T) {
+	res := IGRFresults{Declination: 1, DeclinationSV: 2, Inclination: 3, InclinationSV: 4, HorizontalIntensity: 5, HorizontalSV: 6, NorthComponent: 7,
+		NorthSV: 8, EastComponent: 9, EastSV: 10, VerticalComponent: 11, VerticalSV: 12, TotalIntensity: 13, TotalSV: 14}
+	tests := []struct {
+		component Component
+		name      string
+		unit      string
+		value     float64
+	}{
+		{component: Declination, name: "Declination", unit: "deg", value: 1},
+		{component: InclinationSV, name: "InclinationSV", unit: "arcmin/yr", value: 4},
+		{component: HorizontalIntensity, name: "HorizontalIntensity", unit: "nT", value: 5},
+		{component: EastSV, name: "EastSV", unit: "nT/yr", value: 10},
+		{component: TotalSV, name: "TotalSV", unit: "nT/yr", value: 14},
+		{component: Component(-1), name: "Component(-1)", unit: "", value: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.component.String(); got != tt.name {
+				t.Errorf("String() = %v, want %v", got, tt.name)
+			}
+			if got := tt.component.Unit(); got != tt.unit {
+				t.Errorf("Unit() = %v, want %v", got, tt.unit)
+			}
+			if got := tt.component.Value(res); got != tt.value {
+				t.Errorf("Value() = %v, want %v", got, tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package igrf
+
+import (
+	"errors"
+)
+
+// the maximal length of the field line to the conjugate point, km, lines from polar caps reach thousands
+// of Earth radii, longer lines are considered open
+const conjugate_max_length = 1e8
+
+// ConjugateResults represents the magnetically conjugate point.
+//
+// Conjugate - the point at the same altitude in the opposite hemisphere on the same field line.
+//
+// Apex - the highest point of the connecting field line, its altitude is the apex altitude.
+type ConjugateResults struct {
+	Conjugate Point
+	Apex      Point
+}
+
+// ConjugatePoint computes the magnetically conjugate point of the `point` for the decimal `date`
+// by tracing along the field line, see `TraceFieldLine`. The conjugate point is at the altitude of the `point`
+// in the opposite magnetic hemisphere.
+//
+// An error is returned if the line is longer than 1e8 km or doesn't return to the altitude of the point.
+func (igd *IGRFdata) ConjugatePoint(point Point, date float64) (ConjugateResults, error) {
+	opts := TraceOptions{TargetAlt: point.Alt, MaxLength: conjugate_max_length}
+	if err := checkTraceConditions(point, opts); err != nil {
+		return ConjugateResults{}, err
+	}
+	tr, err := igd.newTracer(date, opts)
+	if err != nil {
+		return ConjugateResults{}, err
+	}
+	return tr.conjugate(point)
+}
+
+// Traces the field line from the `point` upwards to the target altitude in the opposite magnetic hemisphere.
+func (tr *tracer) conjugate(point Point) (ConjugateResults, error) {
+	// the field goes down in the northern magnetic hemisphere, so the conjugate point is traced against the field
+	north := tr.northern(point)
+	sign := 1.0
+	if north {
+		sign = -1.0
+	}
+	points, found := tr.trace(toECEF(point), sign)
+	if !found {
+		return ConjugateResults{}, errors.New("the field line doesn't return to the altitude of the point")
+	}
+	conjugate := points[len(points)-1]
+	// the apex search needs points ordered along the field
+	if north {
+		reversePoints(points)
+	}
+	return ConjugateResults{Conjugate: conjugate, Apex: tr.apex(points)}, nil
+}
@@ -0,0 +1,81 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_ConjugatePoint(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		point   Point
+		wantErr bool
+	}{
+		{name: "Northern hemisphere", point: Point{60, 30, 0}},
+		{name: "Southern hemisphere", point: Point{-61.4, -125.9, 110}},
+		{name: "Near the dip equator", point: Point{5, 100, 0}},
+		{name: "Above the equator", point: Point{0, 100, 3000}},
+		{name: "High latitude", point: Point{85, 0, 0}},
+		{name: "High southern latitude", point: Point{-75, 120, 200}},
+		{name: "Altitude below the limit", point: Point{60, 30, -5}, wantErr: true},
+		{name: "Open line", point: Point{84, -85, 0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.ConjugatePoint(tt.point, 2020.0)
+			if !checkError(t, "ConjugatePoint", err, tt.wantErr) {
+				return
+			}
+			if distance(toECEF(got.Conjugate), toECEF(tt.point)) < 100 {
+				t.Errorf("ConjugatePoint() = %v, want a point in the opposite hemisphere", got.Conjugate)
+			}
+			if math.Abs(got.Conjugate.Alt-tt.point.Alt) > 1e-5 {
+				t.Errorf("ConjugatePoint() altitude = %v, want %v", got.Conjugate.Alt, tt.point.Alt)
+			}
+			if got.Apex.Alt < tt.point.Alt {
+				t.Errorf("ConjugatePoint() apex altitude = %v, want above %v", got.Apex.Alt, tt.point.Alt)
+			}
+			// the conjugate of the conjugate point is the point itself, on the same field line
+			back, err := igrf_data.ConjugatePoint(got.Conjugate, 2020.0)
+			if err != nil {
+				t.Fatalf("ConjugatePoint() error = %v", err)
+			}
+			if math.Abs(back.Conjugate.Lat-tt.point.Lat) > 1e-3 || math.Abs(back.Conjugate.Lon-tt.point.Lon) > 1e-3 {
+				t.Errorf("ConjugatePoint() of %v = %v, want %v", got.Conjugate, back.Conjugate, tt.point)
+			}
+			if !isClose(back.Apex.Alt, got.Apex.Alt, 1e-6, 0.1) {
+				t.Errorf("ConjugatePoint() apex altitude = %v, want %v", back.Apex.Alt, got.Apex.Alt)
+			}
+		})
+	}
+}
+
+func TestTracerConjugate(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name      string
+		point     Point
+		maxLength float64
+		wantErr   bool
+	}{
+		{name: "Closed line", point: Point{60, 30, 0}, maxLength: 50000},
+		{name: "Overlong line", point: Point{60, 30, 0}, maxLength: 1000, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := igrf_data.newTracer(2020.0, TraceOptions{TargetAlt: tt.point.Alt, MaxLength: tt.maxLength})
+			if err != nil {
+				t.Fatalf("newTracer() error = %v", err)
+			}
+			got, err := tr.conjugate(tt.point)
+			if !checkError(t, "conjugate", err, tt.wantErr) {
+				return
+			}
+			// the conjugate point is in the southern magnetic hemisphere
+			if tr.northern(got.Conjugate) {
+				t.Errorf("conjugate() = %v, want a point in the southern magnetic hemisphere", got.Conjugate)
+			}
+		})
+	}
+}
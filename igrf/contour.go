@@ -0,0 +1,236 @@
+package igrf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Contour is an isoline of a component.
+//
+// Points - the polyline, longitudes jump by 360° where the line crosses the ±180° meridian.
+//
+// Closed - the line is a loop, then the last point is the same as the first one.
+type Contour struct {
+	Level  float64
+	Points []Point
+	Closed bool
+}
+
+// Contours extracts isolines of the `component` for every value of `levels` from the grid defined by `spec`
+// with a single altitude, see `Grid`, by the marching squares method. E.g. the agonic line is the contour
+// of `Declination` at 0.
+//
+// If the grid covers all longitudes, lines are continued across the ±180° meridian.
+// Declination jumps from 180° to -180° to the sides of its poles, there are no contours along this jump.
+func (igd *IGRFdata) Contours(component Component, levels []float64, spec GridSpec) ([]Contour, error) {
+	if !component.valid() {
+		return nil, fmt.Errorf("unknown component %v", component)
+	}
+	if spec.AltStep != 0 && spec.AltMax != spec.AltMin {
+		return nil, errors.New("contours need the grid with a single altitude")
+	}
+	spec.AltStep = 0
+	res, err := igd.Grid(spec)
+	if err != nil {
+		return nil, err
+	}
+	grid := newContourGrid(res, component)
+	contours := make([]Contour, 0)
+	for _, level := range levels {
+		contours = append(contours, grid.contours(level)...)
+	}
+	return contours, nil
+}
+
+// values of a component on a lat/lon grid
+type contourGrid struct {
+	lats, lons []float64
+	values     [][]float64
+	alt        float64
+	// the number of grid columns, the extra one repeats the first column if the grid wraps around
+	columns int
+	wrap    bool
+	// cells with the range of values above this one are skipped, it's for the jump of angles
+	max_range float64
+}
+
+// Returns the grid of the `component` values from `res`.
+func newContourGrid(res *GridResults, component Component) *contourGrid {
+	grid := &contourGrid{lats: res.Lats, lons: res.Lons, alt: res.Alts[0], max_range: math.Inf(1)}
+	if component == Declination {
+		grid.max_range = 180
+	}
+	// the column of 180° is the same as -180°
+	if len(grid.lons) > 1 && grid.lons[len(grid.lons)-1]-grid.lons[0] >= 360-1e-9 {
+		grid.lons = grid.lons[:len(grid.lons)-1]
+	}
+	grid.columns = len(grid.lons)
+	if len(grid.lons) > 1 {
+		step := grid.lons[1] - grid.lons[0]
+		grid.wrap = grid.lons[len(grid.lons)-1]-grid.lons[0]+step >= 360-1e-9
+	}
+	if grid.wrap {
+		grid.columns++
+	}
+	grid.values = make([][]float64, len(grid.lats))
+	for lat_index := range grid.lats {
+		grid.values[lat_index] = make([]float64, len(grid.lons))
+		for lon_index := range grid.lons {
+			grid.values[lat_index][lon_index] = component.Value(res.At(0, lat_index, lon_index))
+		}
+	}
+	return grid
+}
+
+// Returns the value at the row `i` and the column `j`, the extra column repeats the first one.
+func (grid *contourGrid) value(i, j int) float64 {
+	return grid.values[i][j%len(grid.lons)]
+}
+
+// Returns the longitude of the column `j`, the extra column is the first one shifted by 360°.
+func (grid *contourGrid) lon(j int) float64 {
+	if j == len(grid.lons) {
+		return grid.lons[0] + 360
+	}
+	return grid.lons[j]
+}
+
+// a piece of a contour within a single cell, between crossing points on cell edges
+type contourSegment struct {
+	edges  [2]int
+	points [2]Point
+}
+
+// Returns the identifier of the cell edge from the node `i`, `j` to the next one along latitude (`vertical`)
+// or longitude. Edges of the extra column are edges of the first one.
+func (grid *contourGrid) edgeID(i, j int, vertical bool) int {
+	if vertical {
+		return 2*(i*grid.columns+j%len(grid.lons)) + 1
+	}
+	return 2 * (i*grid.columns + j)
+}
+
+// Returns the point where the `level` crosses the cell edge, see `edgeID`.
+func (grid *contourGrid) crossing(i, j int, vertical bool, level float64) Point {
+	a := grid.value(i, j)
+	point := Point{Lat: grid.lats[i], Lon: grid.lon(j), Alt: grid.alt}
+	if vertical {
+		point.Lat += (level - a) / (grid.value(i+1, j) - a) * (grid.lats[i+1] - grid.lats[i])
+		if j == len(grid.lons) {
+			point.Lon = grid.lons[0]
+		}
+		return point
+	}
+	point.Lon += (level - a) / (grid.value(i, j+1) - a) * (grid.lon(j+1) - grid.lon(j))
+	if point.Lon > 180 {
+		point.Lon -= 360
+	}
+	return point
+}
+
+// Returns segments of the `level` contour in every cell of the grid.
+func (grid *contourGrid) segments(level float64) []contourSegment {
+	segments := make([]contourSegment, 0)
+	for i := 0; i < len(grid.lats)-1; i++ {
+		for j := 0; j < grid.columns-1; j++ {
+			// corners counterclockwise from the south-west one
+			corners := [4]float64{grid.value(i, j), grid.value(i, j+1), grid.value(i+1, j+1), grid.value(i+1, j)}
+			low, high := corners[0], corners[0]
+			for _, value := range corners {
+				low, high = math.Min(low, value), math.Max(high, value)
+			}
+			if math.IsNaN(low) || math.IsNaN(high) || high-low > grid.max_range || level < low || level > high {
+				continue
+			}
+			// cell edges: south, east, north, west, each one from the corner with the same index
+			type edge struct {
+				i, j     int
+				vertical bool
+			}
+			edges := [4]edge{{i, j, false}, {i, j + 1, true}, {i + 1, j, false}, {i, j, true}}
+			crossed := make([]int, 0, 4)
+			for k := 0; k < 4; k++ {
+				if (corners[k] >= level) != (corners[(k+1)%4] >= level) {
+					crossed = append(crossed, k)
+				}
+			}
+			pairs := [][2]int{}
+			switch len(crossed) {
+			case 2:
+				pairs = append(pairs, [2]int{crossed[0], crossed[1]})
+			case 4:
+				// the saddle is resolved by the value in the centre of the cell
+				centre_above := (corners[0]+corners[1]+corners[2]+corners[3])/4 >= level
+				if centre_above == (corners[0] >= level) {
+					// corners 1 and 3 are cut off
+					pairs = append(pairs, [2]int{0, 1}, [2]int{2, 3})
+				} else {
+					// corners 0 and 2 are cut off
+					pairs = append(pairs, [2]int{3, 0}, [2]int{1, 2})
+				}
+			}
+			for _, pair := range pairs {
+				var segment contourSegment
+				for end, k := range pair {
+					e := edges[k]
+					segment.edges[end] = grid.edgeID(e.i, e.j, e.vertical)
+					segment.points[end] = grid.crossing(e.i, e.j, e.vertical, level)
+				}
+				segments = append(segments, segment)
+			}
+		}
+	}
+	return segments
+}
+
+// Returns contours of the `level` joining segments by shared edges, open lines first.
+func (grid *contourGrid) contours(level float64) []Contour {
+	segments := grid.segments(level)
+	by_edge := make(map[int][]int)
+	for index, segment := range segments {
+		for _, id := range segment.edges {
+			by_edge[id] = append(by_edge[id], index)
+		}
+	}
+	used := make([]bool, len(segments))
+	// follows segments from the `end` of the segment `index`
+	walk := func(index, end int) Contour {
+		start := segments[index].edges[end]
+		contour := Contour{Level: level, Points: []Point{segments[index].points[end]}}
+		for {
+			used[index] = true
+			segment := segments[index]
+			node := segment.edges[1-end]
+			contour.Points = append(contour.Points, segment.points[1-end])
+			next := -1
+			for _, candidate := range by_edge[node] {
+				if !used[candidate] {
+					next = candidate
+				}
+			}
+			if next < 0 {
+				contour.Closed = node == start && len(contour.Points) > 2
+				return contour
+			}
+			index, end = next, 0
+			if segments[next].edges[1] == node {
+				end = 1
+			}
+		}
+	}
+	contours := make([]Contour, 0)
+	for index, segment := range segments {
+		for end, id := range segment.edges {
+			if !used[index] && len(by_edge[id]) == 1 {
+				contours = append(contours, walk(index, end))
+			}
+		}
+	}
+	for index := range segments {
+		if !used[index] {
+			contours = append(contours, walk(index, 0))
+		}
+	}
+	return contours
+}
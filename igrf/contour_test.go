@@ -0,0 +1,119 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_Contours(t *testing.T) {
+	igrf_data := New()
+	global := GridSpec{LatMin: -80, LatMax: 80, LatStep: 2, LonMin: -180, LonMax: 180, LonStep: 2, Date: 2020.0}
+	tests := []struct {
+		name      string
+		component Component
+		levels    []float64
+		spec      GridSpec
+		// maximum difference between the value at contour points and the level
+		tolerance float64
+		wantErr   bool
+	}{
+		{name: "Isodynamic lines", component: TotalIntensity, levels: []float64{30000, 50000}, spec: global, tolerance: 200},
+		{name: "Agonic line", component: Declination, levels: []float64{0}, spec: global, tolerance: 1},
+		{name: "Dip equator", component: Inclination, levels: []float64{0}, spec: global, tolerance: 0.5},
+		{name: "Regional isoclinic lines", component: Inclination, levels: []float64{60, 70}, spec: GridSpec{LatMin: 40, LatMax: 70, LatStep: 1, LonMin: 0, LonMax: 60, LonStep: 1, Date: 2020.0}, tolerance: 0.1},
+		{name: "Unknown component", component: Component(100), levels: []float64{0}, spec: global, wantErr: true},
+		{name: "Several altitudes", component: TotalIntensity, levels: []float64{0}, spec: GridSpec{LatMin: -10, LatMax: 10, LatStep: 1, LonMin: -10, LonMax: 10, LonStep: 1, AltMin: 0, AltMax: 100, AltStep: 50, Date: 2020.0}, wantErr: true},
+		{name: "Incorrect grid", component: TotalIntensity, levels: []float64{0}, spec: GridSpec{LatMin: 10, LatMax: -10, LatStep: 1, LonMin: -10, LonMax: 10, LonStep: 1, Date: 2020.0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.Contours(tt.component, tt.levels, tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Contours() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			levels := make(map[float64]bool)
+			for _, contour := range got {
+				levels[contour.Level] = true
+				if len(contour.Points) < 2 {
+					t.Errorf("Contours() level %v has %v points", contour.Level, len(contour.Points))
+				}
+				if contour.Closed && contour.Points[0] != contour.Points[len(contour.Points)-1] {
+					t.Errorf("Contours() level %v is closed, but ends at %v, not at %v", contour.Level, contour.Points[len(contour.Points)-1], contour.Points[0])
+				}
+				for _, point := range contour.Points {
+					res, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, tt.spec.Date)
+					// declination changes fast near dip poles, where all isogonic lines meet
+					if tt.component == Declination && res.HorizontalIntensity < 2000 {
+						continue
+					}
+					if value := tt.component.Value(res); math.Abs(value-contour.Level) > tt.tolerance {
+						t.Errorf("Contours() level %v point %v has value %v", contour.Level, point, value)
+					}
+				}
+			}
+			for _, level := range tt.levels {
+				if !levels[level] {
+					t.Errorf("Contours() has no lines of level %v", level)
+				}
+			}
+		})
+	}
+}
+
+func TestIGRFdata_ContoursWrap(t *testing.T) {
+	igrf_data := New()
+	// both the dip equator and the isoclinic line of 70° go around the globe
+	for _, spec := range []GridSpec{
+		{LatMin: -40, LatMax: 80, LatStep: 2, LonMin: -180, LonMax: 180, LonStep: 5, Date: 2020.0},
+		{LatMin: -40, LatMax: 80, LatStep: 2, LonMin: -180, LonMax: 175, LonStep: 5, Date: 2020.0},
+	} {
+		got, err := igrf_data.Contours(Inclination, []float64{0, 70}, spec)
+		if err != nil {
+			t.Fatalf("Contours() error = %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("Contours() with longitudes up to %v returned %v lines, want 2", spec.LonMax, len(got))
+		}
+		for _, contour := range got {
+			if !contour.Closed {
+				t.Errorf("Contours() with longitudes up to %v level %v is not closed", spec.LonMax, contour.Level)
+			}
+			for _, point := range contour.Points {
+				if point.Lon < -180 || point.Lon >= 180 {
+					t.Errorf("Contours() level %v point %v is out of range", contour.Level, point)
+				}
+			}
+		}
+	}
+	// without the wraparound the line is open at the grid edges
+	got, err := igrf_data.Contours(Inclination, []float64{0}, GridSpec{LatMin: -40, LatMax: 40, LatStep: 2, LonMin: -180, LonMax: 170, LonStep: 5, Date: 2020.0})
+	if err != nil {
+		t.Fatalf("Contours() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Closed {
+		t.Errorf("Contours() of the partial grid returned %v lines, want a single open line", len(got))
+	}
+}
+
+func TestContourGridSaddle(t *testing.T) {
+	// a single cell with two corners above the level, the centre is above too
+	grid := &contourGrid{lats: []float64{0, 1}, lons: []float64{0, 1}, values: [][]float64{{1, 0}, {0, 1}}, columns: 2, max_range: math.Inf(1)}
+	got := grid.contours(0.4)
+	if len(got) != 2 {
+		t.Fatalf("contours() returned %v lines, want 2", len(got))
+	}
+	for _, contour := range got {
+		if len(contour.Points) != 2 || contour.Closed {
+			t.Errorf("contours() returned %v", contour)
+		}
+	}
+	// the jump of values is skipped
+	grid.max_range = 0.5
+	if got := grid.contours(0.4); len(got) != 0 {
+		t.Errorf("contours() across the jump returned %v", got)
+	}
+}
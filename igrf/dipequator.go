@@ -0,0 +1,79 @@
+package igrf
+
+import "fmt"
+
+const (
+	// the dip equator is searched within this range of latitudes, decimal degrees
+	dip_equator_max_lat = 30.0
+	// the step of the search for a sign change of inclination, decimal degrees
+	dip_equator_scan_step = 1.0
+	// the accuracy of latitudes of the dip equator, decimal degrees
+	dip_equator_tolerance = 1e-9
+)
+
+// DipEquator returns the dip (magnetic) equator, the line where inclination is zero, at the altitude `alt`
+// (km, see `IGRF`) for the decimal `date`.
+//
+// The equator is found on meridians from -180° to 180° with the step `lon_step` (decimal degrees),
+// the last meridian is always 180°, so the polyline is closed.
+func (igd *IGRFdata) DipEquator(date, alt, lon_step float64) ([]Point, error) {
+	if err := checkInitialConditions(0, 0, alt); err != nil {
+		return nil, err
+	}
+	lons, err := gridAxis("longitude", -180, 180, lon_step)
+	if err != nil {
+		return nil, err
+	}
+	if lons[len(lons)-1] < 180 {
+		lons = append(lons, 180)
+	}
+	snapshot, err := igd.Snapshot(date)
+	if err != nil {
+		return nil, err
+	}
+	line := make([]Point, 0, len(lons))
+	for _, lon := range lons {
+		lat, err := dipEquatorLatitude(snapshot, lon, alt)
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, Point{Lat: lat, Lon: lon, Alt: alt})
+	}
+	return line, nil
+}
+
+// Finds the latitude where inclination is zero on the meridian `lon` by bisection,
+// the inclination is positive (down) to the north of the dip equator.
+func dipEquatorLatitude(snapshot *Snapshot, lon, alt float64) (float64, error) {
+	inclination := func(lat float64) float64 {
+		res, _ := snapshot.Field(lat, lon, alt)
+		return res.Inclination
+	}
+	// the bracket closest to the geographic equator
+	var low, high float64
+	found := false
+	for offset := 0.0; offset < dip_equator_max_lat && !found; offset += dip_equator_scan_step {
+		for _, sign := range []float64{1, -1} {
+			a, b := sign*offset, sign*(offset+dip_equator_scan_step)
+			if a > b {
+				a, b = b, a
+			}
+			if inclination(a) <= 0 && inclination(b) >= 0 {
+				low, high, found = a, b, true
+				break
+			}
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("dip equator is not found at longitude %v°", lon)
+	}
+	for high-low > dip_equator_tolerance {
+		middle := (low + high) / 2
+		if inclination(middle) < 0 {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return (low + high) / 2, nil
+}
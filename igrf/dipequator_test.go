@@ -0,0 +1,49 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_DipEquator(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name      string
+		date      float64
+		alt       float64
+		lon_step  float64
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "2020", date: 2020.0, alt: 0, lon_step: 10, wantCount: 37},
+		{name: "1900 at 110 km", date: 1900.0, alt: 110, lon_step: 7, wantCount: 53},
+		{name: "Zero step", date: 2020.0, lon_step: 0, wantErr: true},
+		{name: "Altitude out of range", date: 2020.0, alt: 1000, lon_step: 10, wantErr: true},
+		{name: "Date out of range", date: 1800.0, lon_step: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.DipEquator(tt.date, tt.alt, tt.lon_step)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DipEquator() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != tt.wantCount || got[0].Lon != -180 || got[len(got)-1].Lon != 180 {
+				t.Fatalf("DipEquator() has %v points from %v to %v, want %v points from -180 to 180", len(got), got[0], got[len(got)-1], tt.wantCount)
+			}
+			// -180° and 180° differ a bit as the approximate degrees to radians factor is used by calc.Shval3
+			if math.Abs(got[0].Lat-got[len(got)-1].Lat) > 1e-4 {
+				t.Errorf("DipEquator() is not closed: %v, %v", got[0], got[len(got)-1])
+			}
+			for _, point := range got {
+				res, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, tt.date)
+				if math.Abs(res.Inclination) > 1e-6 || math.Abs(point.Lat) > 20 || point.Alt != tt.alt {
+					t.Errorf("DipEquator() point %v has inclination %v", point, res.Inclination)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package igrf
+
+import (
+	"math"
+
+	"github.com/proway2/go-igrf/calc"
+)
+
+// WGS84 semi-major axis and flattening
+const (
+	wgs84_a = 6378137.0
+	wgs84_f = 1 / 298.257223563
+)
+
+// ECEFresults represents the geomagnetic field in Earth-centred Earth-fixed Cartesian coordinates.
+//
+// Bx, By, Bz - components in nT, X towards 0° latitude and 0° longitude, Z towards the North pole.
+//
+// BxSV, BySV, BzSV - SV of components in nT/yr.
+type ECEFresults struct {
+	Bx   float64
+	By   float64
+	Bz   float64
+	BxSV float64
+	BySV float64
+	BzSV float64
+}
+
+// FieldECEF computes the geomagnetic field and its SV in ECEF coordinates at the ECEF position
+// x, y, z in metres for the decimal `date`.
+//
+// The position must not be deeper than 1 km below the WGS84 polar radius.
+func (igd *IGRFdata) FieldECEF(x, y, z, date float64) (ECEFresults, error) {
+	radius := math.Sqrt(x*x+y*y+z*z) / 1000.0
+	if err := checkGeocentricConditions(0, 0, radius); err != nil {
+		return ECEFresults{}, err
+	}
+	lat := rad2deg(math.Asin(z / 1000.0 / radius))
+	lon := rad2deg(math.Atan2(y, x))
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return ECEFresults{}, err
+	}
+	ws := calc.NewWorkspace(set.nmax)
+	ws.SetGeocentric(lat, radius, set.nmax)
+	ws.SetLongitude(lon, set.nmax)
+	north, east, down, north_dot, east_dot, down_dot := set.sum(ws)
+	bx, by, bz := NEDToECEF(lat, lon, north, east, down)
+	bx_dot, by_dot, bz_dot := NEDToECEF(lat, lon, north_dot, east_dot, down_dot)
+	return ECEFresults{
+		Bx:   bx,
+		By:   by,
+		Bz:   bz,
+		BxSV: bx_dot,
+		BySV: by_dot,
+		BzSV: bz_dot,
+	}, nil
+}
+
+// GeodeticToECEF converts WGS84 geodetic latitude, longitude (decimal degrees)
+// and altitude above mean sea level (km) into ECEF x, y, z in metres.
+func GeodeticToECEF(lat, lon, alt float64) (float64, float64, float64) {
+	slat, clat := math.Sincos(lat * math.Pi / 180)
+	slon, clon := math.Sincos(lon * math.Pi / 180)
+	e2 := wgs84_f * (2 - wgs84_f)
+	// prime vertical radius of curvature
+	n := wgs84_a / math.Sqrt(1-e2*slat*slat)
+	h := alt * 1000.0
+	x := (n + h) * clat * clon
+	y := (n + h) * clat * slon
+	z := (n*(1-e2) + h) * slat
+	return x, y, z
+}
+
+// NEDToECEF rotates north, east and down components at the point with latitude `lat` and longitude `lon`
+// (decimal degrees) into ECEF components. Geodetic latitude must be used for the geodetic frame of `IGRF`
+// and geocentric latitude for the spherical frame of `Geocentric` results.
+func NEDToECEF(lat, lon, north, east, down float64) (float64, float64, float64) {
+	slat, clat := math.Sincos(lat * math.Pi / 180)
+	slon, clon := math.Sincos(lon * math.Pi / 180)
+	x := -slat*clon*north - slon*east - clat*clon*down
+	y := -slat*slon*north + clon*east - clat*slon*down
+	z := clat*north - slat*down
+	return x, y, z
+}
+
+// ECEFToGeodetic converts ECEF x, y, z in metres into WGS84 geodetic latitude, longitude (decimal degrees)
+// and altitude above mean sea level (km), it's the inverse of `GeodeticToECEF`.
+func ECEFToGeodetic(x, y, z float64) (float64, float64, float64) {
+	e2 := wgs84_f * (2 - wgs84_f)
+	p := math.Hypot(x, y)
+	lon := math.Atan2(y, x)
+	lat := math.Atan2(z, p*(1-e2))
+	var h float64
+	for i := 0; i < 10; i++ {
+		slat, clat := math.Sincos(lat)
+		n := wgs84_a / math.Sqrt(1-e2*slat*slat)
+		// the altitude is taken along the larger of projections to avoid division by zero near poles
+		if clat > math.Abs(slat) {
+			h = p/clat - n
+		} else {
+			h = z/slat - n*(1-e2)
+		}
+		lat = math.Atan2(z, p*(1-e2*n/(n+h)))
+	}
+	return rad2deg(lat), rad2deg(lon), h / 1000.0
+}
@@ -0,0 +1,60 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeodeticToECEF(t *testing.T) {
+	tests := []struct {
+		name          string
+		lat, lon, alt float64
+		x, y, z       float64
+	}{
+		{name: "Equator, prime meridian", lat: 0, lon: 0, alt: 0, x: 6378137.0, y: 0, z: 0},
+		{name: "Equator, 90° east, 1 km", lat: 0, lon: 90, alt: 1, x: 0, y: 6379137.0, z: 0},
+		{name: "North pole", lat: 90, lon: 0, alt: 0, x: 0, y: 0, z: 6356752.314},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, z := GeodeticToECEF(tt.lat, tt.lon, tt.alt)
+			if math.Abs(x-tt.x) > 0.001 || math.Abs(y-tt.y) > 0.001 || math.Abs(z-tt.z) > 0.001 {
+				t.Errorf("GeodeticToECEF() = (%v, %v, %v), want (%v, %v, %v)", x, y, z, tt.x, tt.y, tt.z)
+			}
+		})
+	}
+}
+
+func TestECEFToGeodetic(t *testing.T) {
+	points := []Point{{0, 0, 0}, {59.9, 39.9, 1.1}, {-45.5, -120.3, 300}, {90, 0, 0}, {-89.9999, 170, 25000}, {30, 60, -1}}
+	for _, point := range points {
+		lat, lon, alt := ECEFToGeodetic(GeodeticToECEF(point.Lat, point.Lon, point.Alt))
+		if math.Abs(lat-point.Lat) > 1e-9 || math.Abs(alt-point.Alt) > 1e-6 || (math.Abs(point.Lat) < 90 && math.Abs(lon-point.Lon) > 1e-9) {
+			t.Errorf("ECEFToGeodetic(GeodeticToECEF(%v)) = (%v, %v, %v)", point, lat, lon, alt)
+		}
+	}
+}
+
+func TestIGRFdata_FieldECEF(t *testing.T) {
+	igrf_data := New()
+	points := []Point{{59.9, 39.9, 0.0}, {-64.081, 135.866, 0.0}, {0.0, -120.0, 300.0}, {-33.3, 170.0, 600.0}}
+	for _, point := range points {
+		x, y, z := GeodeticToECEF(point.Lat, point.Lon, point.Alt)
+		got, err := igrf_data.FieldECEF(x, y, z, 2021.5)
+		if err != nil {
+			t.Fatalf("FieldECEF() error = %v", err)
+		}
+		want, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, 2021.5)
+		bx, by, bz := NEDToECEF(point.Lat, point.Lon, want.NorthComponent, want.EastComponent, want.VerticalComponent)
+		// degrees to radians conversion of the C implementation is approximate
+		if math.Abs(got.Bx-bx) > 0.5 || math.Abs(got.By-by) > 0.5 || math.Abs(got.Bz-bz) > 0.5 {
+			t.Errorf("FieldECEF() at %v = (%v, %v, %v), want (%v, %v, %v)", point, got.Bx, got.By, got.Bz, bx, by, bz)
+		}
+		if f := math.Sqrt(got.Bx*got.Bx + got.By*got.By + got.Bz*got.Bz); math.Abs(f-want.TotalIntensity) > 0.5 {
+			t.Errorf("FieldECEF() at %v intensity = %v, want %v", point, f, want.TotalIntensity)
+		}
+	}
+	if _, err := igrf_data.FieldECEF(1000.0, 0, 0, 2021.5); err == nil {
+		t.Errorf("FieldECEF() expected an error for the point near the Earth's centre")
+	}
+}
@@ -0,0 +1,85 @@
+package igrf
+
+import (
+	"fmt"
+
+	"github.com/proway2/go-igrf/calc"
+)
+
+// CoordinateSystem defines how input coordinates and output components are interpreted, see `IGRFIn`.
+type CoordinateSystem int
+
+const (
+	// Geodetic (WGS84) latitude and altitude above mean sea level in km,
+	// components are in the local geodetic frame. This is what `IGRF` does.
+	Geodetic CoordinateSystem = iota
+	// Geocentric latitude and the distance from the Earth's centre in km,
+	// components are in the local spherical frame: X = -B_theta, Y = B_phi, Z = -B_r.
+	// This is `itype=2` of the reference FORTRAN implementation, but with latitude instead of colatitude.
+	Geocentric
+)
+
+// the minimal distance from the Earth's centre, WGS84 polar radius minus 1 km
+const min_geocentric_radius = 6356.752 - 1.0
+
+// IGRFIn computes values for the geomagnetic field and secular variation in the coordinate system `cs`.
+//
+// For `Geodetic` `alt_or_radius` is the altitude above mean sea level in km, it's the same as `IGRF`.
+// For `Geocentric` `alt_or_radius` is the distance from the Earth's centre in km, it's the same as `IGRFGeocentric`.
+func (igd *IGRFdata) IGRFIn(cs CoordinateSystem, lat, lon, alt_or_radius, date float64) (IGRFresults, error) {
+	switch cs {
+	case Geodetic:
+		return igd.IGRF(lat, lon, alt_or_radius, date)
+	case Geocentric:
+		return igd.IGRFGeocentric(lat, lon, alt_or_radius, date)
+	}
+	return IGRFresults{}, fmt.Errorf("unknown coordinate system %v", cs)
+}
+
+// IGRFGeocentric computes values for the geomagnetic field and secular variation for geocentric coordinates.
+//
+// lat - geocentric latitude in decimal degrees (-90.0 to 90.0). Unlike `itype=2` of the reference FORTRAN
+// implementation, which takes colatitude, this is latitude: lat = 90 - colatitude.
+//
+// radius - the distance from the Earth's centre in km (not less than 6355.752 km), not the altitude.
+//
+// Components are in the local spherical frame, see `IGRFresults.Spherical`. D, I, H and F are computed
+// from these components.
+func (igd *IGRFdata) IGRFGeocentric(lat, lon, radius, date float64) (IGRFresults, error) {
+	if err := checkGeocentricConditions(lat, lon, radius); err != nil {
+		return IGRFresults{}, err
+	}
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return IGRFresults{}, err
+	}
+	ws := calc.NewWorkspace(set.nmax)
+	ws.SetGeocentric(lat, radius, set.nmax)
+	ws.SetLongitude(lon, set.nmax)
+	return set.results(ws), nil
+}
+
+// Spherical returns components in the local spherical frame B_r (outward), B_theta (southward), B_phi (eastward) in nT,
+// results must be computed for the `Geocentric` coordinate system.
+func (res IGRFresults) Spherical() (float64, float64, float64) {
+	return -res.VerticalComponent, -res.NorthComponent, res.EastComponent
+}
+
+// SphericalSV returns SV of components in the local spherical frame B_r, B_theta, B_phi in nT/yr,
+// results must be computed for the `Geocentric` coordinate system.
+func (res IGRFresults) SphericalSV() (float64, float64, float64) {
+	return -res.VerticalSV, -res.NorthSV, res.EastSV
+}
+
+func checkGeocentricConditions(lat, lon, radius float64) error {
+	if lat < -90.0 || lat > 90.0 {
+		return fmt.Errorf("Latitude %v° is out of range (-90.0, 90.0)", lat)
+	}
+	if lon < -180.0 || lon > 180.0 {
+		return fmt.Errorf("Longitude %v° is out of range (-180.0, 180.0)", lon)
+	}
+	if !(radius >= min_geocentric_radius) {
+		return fmt.Errorf("Radius %v km is less than %v km", radius, min_geocentric_radius)
+	}
+	return nil
+}
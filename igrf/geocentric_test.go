@@ -0,0 +1,90 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_IGRFIn(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		cs      CoordinateSystem
+		lat     float64
+		radius  float64
+		alt     float64
+		wantErr bool
+	}{
+		// on the equator and at poles geodetic and geocentric latitudes and frames are the same
+		{name: "Equator", cs: Geocentric, lat: 0.0, radius: 6378.137 + 100.0, alt: 100.0},
+		{name: "Near North pole", cs: Geocentric, lat: 89.9999, radius: 6356.752 + 10.0, alt: 10.0},
+		{name: "Geodetic", cs: Geodetic, lat: 45.0, radius: 10.0, alt: 10.0},
+		{name: "Radius below the surface", cs: Geocentric, lat: 0.0, radius: 6000.0, wantErr: true},
+		{name: "Unknown coordinate system", cs: CoordinateSystem(5), lat: 0.0, radius: 6400.0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.IGRFIn(tt.cs, tt.lat, 39.9, tt.radius, 2021.5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IGRFIn() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			want, _ := igrf_data.IGRF(tt.lat, 39.9, tt.alt, 2021.5)
+			if !isClose(got.NorthComponent, want.NorthComponent, 1e-6, 0.01) ||
+				!isClose(got.EastComponent, want.EastComponent, 1e-6, 0.01) ||
+				!isClose(got.VerticalComponent, want.VerticalComponent, 1e-6, 0.01) ||
+				!isClose(got.NorthSV, want.NorthSV, 1e-6, 0.01) {
+				t.Errorf("IGRFIn() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestIGRFdata_IGRFInRotation(t *testing.T) {
+	// geodetic and geocentric components at the same point differ by the rotation between frames
+	igrf_data := New()
+	lat, alt := 59.9, 1.1
+	a2, b2 := 40680631.59, 40408299.98
+	slat, clat := math.Sincos(lat * math.Pi / 180)
+	// geocentric coordinates of the geodetic point
+	rho := math.Sqrt(a2*clat*clat + b2*slat*slat)
+	radius := math.Sqrt(alt*(alt+2.0*rho) + (a2*a2*clat*clat+b2*b2*slat*slat)/(rho*rho))
+	cd := (alt + rho) / radius
+	sd := (a2 - b2) / rho * slat * clat / radius
+	gc_lat := math.Asin(slat*cd-clat*sd) * 180 / math.Pi
+	geodetic, _ := igrf_data.IGRF(lat, 39.9, alt, 2021.5)
+	geocentric, err := igrf_data.IGRFIn(Geocentric, gc_lat, 39.9, radius, 2021.5)
+	if err != nil {
+		t.Fatalf("IGRFIn() error = %v", err)
+	}
+	br, btheta, bphi := geocentric.Spherical()
+	x := -btheta*cd - br*sd
+	z := -br*cd + btheta*sd
+	if math.Abs(x-geodetic.NorthComponent) > 0.1 || math.Abs(bphi-geodetic.EastComponent) > 0.1 || math.Abs(z-geodetic.VerticalComponent) > 0.1 {
+		t.Errorf("IGRFIn() rotated = (%v, %v, %v), want (%v, %v, %v)", x, bphi, z, geodetic.NorthComponent, geodetic.EastComponent, geodetic.VerticalComponent)
+	}
+	if math.Abs(geocentric.TotalIntensity-geodetic.TotalIntensity) > 0.01 {
+		t.Errorf("IGRFIn() F = %v, want %v", geocentric.TotalIntensity, geodetic.TotalIntensity)
+	}
+}
+
+func TestIGRFdata_IGRFGeocentric(t *testing.T) {
+	igrf_data := New()
+	// FORTRAN itype=2 takes colatitude
+	colatitude, radius := 30.0, 6371.2+300.0
+	got, err := igrf_data.IGRFGeocentric(90-colatitude, 39.9, radius, 2021.5)
+	if err != nil {
+		t.Fatalf("IGRFGeocentric() error = %v", err)
+	}
+	want, _ := igrf_data.IGRFIn(Geocentric, 60.0, 39.9, radius, 2021.5)
+	if got != want {
+		t.Errorf("IGRFGeocentric() = %v, IGRFIn() = %v", got, want)
+	}
+	// the altitude instead of the radius
+	if _, err := igrf_data.IGRFGeocentric(60.0, 39.9, 300.0, 2021.5); err == nil {
+		t.Errorf("IGRFGeocentric() expected an error for the radius of 300 km")
+	}
+}
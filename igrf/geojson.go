@@ -0,0 +1,185 @@
+package igrf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// GeoJSON structures, coordinates are [lon, lat] in decimal degrees (RFC 7946)
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// WriteGridGeoJSON writes the grid `res` as a GeoJSON FeatureCollection of points to `w`.
+//
+// Properties of every point are its altitude `alt`, values of `components` by their names
+// (null for NaN) and `units` of the altitude and all components.
+func WriteGridGeoJSON(w io.Writer, res *GridResults, components ...Component) error {
+	if res == nil {
+		return errors.New("grid results are missing")
+	}
+	if len(components) == 0 {
+		return errors.New("no components to export")
+	}
+	units := map[string]string{"alt": "km"}
+	for _, component := range components {
+		if !component.valid() {
+			return fmt.Errorf("unknown component %v", component)
+		}
+		units[component.String()] = component.Unit()
+	}
+	features := make([]geoJSONFeature, 0, len(res.Values))
+	for alt_index, alt := range res.Alts {
+		for lat_index, lat := range res.Lats {
+			for lon_index, lon := range res.Lons {
+				value := res.At(alt_index, lat_index, lon_index)
+				properties := map[string]interface{}{"alt": alt, "units": units}
+				for _, component := range components {
+					properties[component.String()] = jsonValue(component.Value(value))
+				}
+				features = append(features, geoJSONFeature{
+					Type:       "Feature",
+					Geometry:   geoJSONGeometry{Type: "Point", Coordinates: [2]float64{lon, lat}},
+					Properties: properties,
+				})
+			}
+		}
+	}
+	return writeGeoJSON(w, features)
+}
+
+// WriteContoursGeoJSON writes `contours` of the `component`, see `Contours`, as a GeoJSON FeatureCollection
+// of lines to `w`. A line crossing the ±180° meridian is cut there into a MultiLineString.
+//
+// Properties of every line are `component`, `level`, `unit`, `alt` (km) and `closed`.
+func WriteContoursGeoJSON(w io.Writer, contours []Contour, component Component) error {
+	if !component.valid() {
+		return fmt.Errorf("unknown component %v", component)
+	}
+	features := make([]geoJSONFeature, 0, len(contours))
+	for _, contour := range contours {
+		if len(contour.Points) < 2 {
+			continue
+		}
+		features = append(features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: lineGeometry(contour.Points),
+			Properties: map[string]interface{}{
+				"component": component.String(),
+				"level":     contour.Level,
+				"unit":      component.Unit(),
+				"alt":       contour.Points[0].Alt,
+				"closed":    contour.Closed,
+			},
+		})
+	}
+	return writeGeoJSON(w, features)
+}
+
+// WriteTrackGeoJSON writes the `track`, e.g. of `DipEquator` or of dip poles over years, see `DipPoles`,
+// as a GeoJSON FeatureCollection to `w`. The track is a line cut at the ±180° meridian like
+// in `WriteContoursGeoJSON` with properties `name` and `units`.
+//
+// If `dates` are given, one per point of the track, every point is also a feature with properties
+// `name`, `date` (decimal year), `alt` and `units`.
+func WriteTrackGeoJSON(w io.Writer, name string, track []Point, dates []float64) error {
+	if len(track) == 0 {
+		return errors.New("the track is empty")
+	}
+	if dates != nil && len(dates) != len(track) {
+		return fmt.Errorf("%v dates are given for %v points of the track", len(dates), len(track))
+	}
+	units := map[string]string{"alt": "km", "date": "decimal year"}
+	features := make([]geoJSONFeature, 0, len(track)+1)
+	if len(track) > 1 {
+		features = append(features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   lineGeometry(track),
+			Properties: map[string]interface{}{"name": name, "units": units},
+		})
+	}
+	for index, date := range dates {
+		features = append(features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "Point", Coordinates: [2]float64{track[index].Lon, track[index].Lat}},
+			Properties: map[string]interface{}{"name": name, "date": date, "alt": track[index].Alt, "units": units},
+		})
+	}
+	return writeGeoJSON(w, features)
+}
+
+// Writes the FeatureCollection of `features` to `w`.
+func writeGeoJSON(w io.Writer, features []geoJSONFeature) error {
+	return json.NewEncoder(w).Encode(geoJSONFeatureCollection{Type: "FeatureCollection", Features: features})
+}
+
+// Returns LineString of `points` or MultiLineString if the line is cut at the ±180° meridian, see `splitAntimeridian`.
+func lineGeometry(points []Point) geoJSONGeometry {
+	parts := splitAntimeridian(points)
+	if len(parts) == 1 {
+		return geoJSONGeometry{Type: "LineString", Coordinates: parts[0]}
+	}
+	return geoJSONGeometry{Type: "MultiLineString", Coordinates: parts}
+}
+
+// Returns [lon, lat] coordinates of the line split where it crosses the ±180° meridian,
+// i.e. the longitude jumps by more than 180° between points. Both parts end at the meridian.
+func splitAntimeridian(points []Point) [][][2]float64 {
+	parts := [][][2]float64{{{points[0].Lon, points[0].Lat}}}
+	for index := 1; index < len(points); index++ {
+		prev, point := points[index-1], points[index]
+		if delta := point.Lon - prev.Lon; math.Abs(delta) > 180 {
+			// the side of the meridian the previous point is at
+			side := 180.0
+			if delta > 0 {
+				side = -180
+			}
+			// the latitude of the crossing is interpolated along the unwrapped longitude
+			lon := point.Lon + 2*side
+			lat := prev.Lat + (point.Lat-prev.Lat)*(side-prev.Lon)/(lon-prev.Lon)
+			last := len(parts) - 1
+			if prev.Lon != side {
+				parts[last] = append(parts[last], [2]float64{side, lat})
+			}
+			parts = append(parts, [][2]float64{{-side, lat}})
+		}
+		last := len(parts) - 1
+		if position := [2]float64{point.Lon, point.Lat}; position != parts[last][len(parts[last])-1] {
+			parts[last] = append(parts[last], position)
+		}
+	}
+	// parts of a single point at the meridian, e.g. the line ends there
+	result := make([][][2]float64, 0, len(parts))
+	for _, part := range parts {
+		if len(part) > 1 {
+			result = append(result, part)
+		}
+	}
+	if len(result) == 0 {
+		return parts[:1]
+	}
+	return result
+}
+
+// Returns `value` for JSON, nil for NaN and infinities as they are not valid JSON numbers.
+func jsonValue(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return value
+}
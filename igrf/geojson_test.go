@@ -0,0 +1,167 @@
+package igrf
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// a decoded GeoJSON FeatureCollection
+type testFeatureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Type     string `json:"type"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	} `json:"features"`
+}
+
+func decodeGeoJSON(t *testing.T, buf *bytes.Buffer) testFeatureCollection {
+	t.Helper()
+	var collection testFeatureCollection
+	if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatalf("invalid GeoJSON: %v", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		t.Fatalf("GeoJSON type = %v, want FeatureCollection", collection.Type)
+	}
+	return collection
+}
+
+func TestWriteGridGeoJSON(t *testing.T) {
+	res := &GridResults{
+		Lats:   []float64{10, 20},
+		Lons:   []float64{-5},
+		Alts:   []float64{100},
+		Values: []IGRFresults{{Declination: 1.5, TotalIntensity: 45000}, {Declination: math.NaN(), TotalIntensity: 46000}},
+	}
+	var buf bytes.Buffer
+	if err := WriteGridGeoJSON(&buf, res, Declination, TotalIntensity); err != nil {
+		t.Fatalf("WriteGridGeoJSON() error = %v", err)
+	}
+	collection := decodeGeoJSON(t, &buf)
+	if len(collection.Features) != 2 {
+		t.Fatalf("WriteGridGeoJSON() has %v features, want 2", len(collection.Features))
+	}
+	feature := collection.Features[1]
+	var coordinates []float64
+	json.Unmarshal(feature.Geometry.Coordinates, &coordinates)
+	if feature.Geometry.Type != "Point" || !reflect.DeepEqual(coordinates, []float64{-5, 20}) {
+		t.Errorf("WriteGridGeoJSON() geometry = %v %v, want Point [-5 20]", feature.Geometry.Type, coordinates)
+	}
+	want := map[string]interface{}{
+		"alt":            100.0,
+		"Declination":    nil,
+		"TotalIntensity": 46000.0,
+		"units":          map[string]interface{}{"alt": "km", "Declination": "deg", "TotalIntensity": "nT"},
+	}
+	if !reflect.DeepEqual(feature.Properties, want) {
+		t.Errorf("WriteGridGeoJSON() properties = %v, want %v", feature.Properties, want)
+	}
+	if err := WriteGridGeoJSON(&buf, res); err == nil {
+		t.Errorf("WriteGridGeoJSON() expected an error without components")
+	}
+	if err := WriteGridGeoJSON(&buf, res, Component(100)); err == nil {
+		t.Errorf("WriteGridGeoJSON() expected an error for an unknown component")
+	}
+	if err := WriteGridGeoJSON(&buf, nil, Declination); err == nil {
+		t.Errorf("WriteGridGeoJSON() expected an error without results")
+	}
+}
+
+func TestWriteContoursGeoJSON(t *testing.T) {
+	contours := []Contour{
+		{Level: 0, Points: []Point{{10, 170, 0}, {12, -170, 0}, {14, -160, 0}}},
+		{Level: 5, Points: []Point{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 0, 0}}, Closed: true},
+	}
+	var buf bytes.Buffer
+	if err := WriteContoursGeoJSON(&buf, contours, Declination); err != nil {
+		t.Fatalf("WriteContoursGeoJSON() error = %v", err)
+	}
+	collection := decodeGeoJSON(t, &buf)
+	if len(collection.Features) != 2 {
+		t.Fatalf("WriteContoursGeoJSON() has %v features, want 2", len(collection.Features))
+	}
+	if got := collection.Features[0].Geometry.Type; got != "MultiLineString" {
+		t.Errorf("WriteContoursGeoJSON() line across 180° is %v, want MultiLineString", got)
+	}
+	if got := collection.Features[1].Geometry.Type; got != "LineString" {
+		t.Errorf("WriteContoursGeoJSON() line is %v, want LineString", got)
+	}
+	want := map[string]interface{}{"component": "Declination", "level": 5.0, "unit": "deg", "alt": 0.0, "closed": true}
+	if got := collection.Features[1].Properties; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteContoursGeoJSON() properties = %v, want %v", got, want)
+	}
+	if err := WriteContoursGeoJSON(&buf, contours, Component(100)); err == nil {
+		t.Errorf("WriteContoursGeoJSON() expected an error for an unknown component")
+	}
+}
+
+func TestWriteTrackGeoJSON(t *testing.T) {
+	track := []Point{{80, 170, 0}, {85, -175, 0}}
+	var buf bytes.Buffer
+	if err := WriteTrackGeoJSON(&buf, "North dip pole", track, []float64{2015, 2020}); err != nil {
+		t.Fatalf("WriteTrackGeoJSON() error = %v", err)
+	}
+	collection := decodeGeoJSON(t, &buf)
+	if len(collection.Features) != 3 {
+		t.Fatalf("WriteTrackGeoJSON() has %v features, want 3", len(collection.Features))
+	}
+	if got := collection.Features[2].Properties["date"]; got != 2020.0 {
+		t.Errorf("WriteTrackGeoJSON() date of the last point = %v, want 2020", got)
+	}
+	buf.Reset()
+	if err := WriteTrackGeoJSON(&buf, "Dip equator", track, nil); err != nil {
+		t.Fatalf("WriteTrackGeoJSON() error = %v", err)
+	}
+	if collection := decodeGeoJSON(t, &buf); len(collection.Features) != 1 {
+		t.Errorf("WriteTrackGeoJSON() without dates has %v features, want 1", len(collection.Features))
+	}
+	if err := WriteTrackGeoJSON(&buf, "", track, []float64{2020}); err == nil {
+		t.Errorf("WriteTrackGeoJSON() expected an error for missing dates")
+	}
+	if err := WriteTrackGeoJSON(&buf, "", nil, nil); err == nil {
+		t.Errorf("WriteTrackGeoJSON() expected an error for the empty track")
+	}
+}
+
+func TestSplitAntimeridian(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		want   [][][2]float64
+	}{
+		{
+			name:   "No crossing",
+			points: []Point{{0, -10, 0}, {1, 10, 0}},
+			want:   [][][2]float64{{{-10, 0}, {10, 1}}},
+		},
+		{
+			name:   "Eastward",
+			points: []Point{{0, 170, 0}, {2, -170, 0}},
+			want:   [][][2]float64{{{170, 0}, {180, 1}}, {{-180, 1}, {-170, 2}}},
+		},
+		{
+			name:   "Westward",
+			points: []Point{{0, -175, 0}, {4, 175, 0}, {5, 170, 0}},
+			want:   [][][2]float64{{{-175, 0}, {-180, 2}}, {{180, 2}, {175, 4}, {170, 5}}},
+		},
+		{
+			name:   "From the meridian",
+			points: []Point{{0, 180, 0}, {1, -170, 0}, {2, -160, 0}},
+			want:   [][][2]float64{{{-180, 0}, {-170, 1}, {-160, 2}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitAntimeridian(tt.points); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitAntimeridian() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
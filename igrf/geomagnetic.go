@@ -0,0 +1,95 @@
+package igrf
+
+import (
+	"errors"
+	"math"
+)
+
+// centred dipole of the field: position of its north pole (the boreal pole, where field lines go down)
+// and the magnitude of the dipole coeffs in nT
+type centredDipole struct {
+	pole_colat, pole_lon float64 // radians
+	b0                   float64
+}
+
+// Returns the centred dipole defined by g10, g11 and h11 coeffs for the `date`.
+func (igd *IGRFdata) dipole(date float64) (centredDipole, error) {
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return centredDipole{}, err
+	}
+	gh := *set.main
+	if len(gh) < 3 {
+		return centredDipole{}, errors.New("dipole coeffs are missing")
+	}
+	g10, g11, h11 := gh[0], gh[1], gh[2]
+	b0 := math.Sqrt(g10*g10 + g11*g11 + h11*h11)
+	if b0 == 0 {
+		return centredDipole{}, errors.New("dipole coeffs are zero")
+	}
+	dp := centredDipole{pole_colat: math.Acos(-g10 / b0), b0: b0}
+	// the longitude of the pole of an axial dipole is zero
+	if g11 != 0 || h11 != 0 {
+		dp.pole_lon = math.Atan2(-h11, -g11)
+	}
+	return dp, nil
+}
+
+// Rotates the unit vector for latitude and longitude (decimal degrees) from geographic into geomagnetic frame,
+// or back if `inverse` is set.
+func (dp centredDipole) rotate(lat, lon float64, inverse bool) (float64, float64) {
+	slat, clat := math.Sincos(lat * math.Pi / 180)
+	slon, clon := math.Sincos(lon * math.Pi / 180)
+	x, y, z := clat*clon, clat*slon, slat
+	st, ct := math.Sincos(dp.pole_colat)
+	sp, cp := math.Sincos(dp.pole_lon)
+	if inverse {
+		// rotation about Y by -colatitude, then about Z by -longitude
+		x, z = x*ct+z*st, -x*st+z*ct
+		x, y = x*cp-y*sp, x*sp+y*cp
+	} else {
+		// rotation about Z by the pole longitude, then about Y by its colatitude
+		x, y = x*cp+y*sp, -x*sp+y*cp
+		x, z = x*ct-z*st, x*st+z*ct
+	}
+	return rad2deg(math.Asin(math.Max(-1, math.Min(1, z)))), rad2deg(math.Atan2(y, x))
+}
+
+// GeomagneticPole returns latitude and longitude (decimal degrees) of the north geomagnetic pole,
+// i.e. the pole of the centred dipole, for the decimal `date`.
+func (igd *IGRFdata) GeomagneticPole(date float64) (float64, float64, error) {
+	dp, err := igd.dipole(date)
+	if err != nil {
+		return 0, 0, err
+	}
+	return 90 - rad2deg(dp.pole_colat), rad2deg(dp.pole_lon), nil
+}
+
+// GeographicToGeomagnetic converts geocentric latitude and longitude (decimal degrees) into centred dipole
+// geomagnetic latitude and longitude for the decimal `date`. Geomagnetic longitude is counted
+// from the meridian through the geographic South pole.
+func (igd *IGRFdata) GeographicToGeomagnetic(lat, lon, date float64) (float64, float64, error) {
+	if err := checkInitialConditions(lat, lon, 0); err != nil {
+		return 0, 0, err
+	}
+	dp, err := igd.dipole(date)
+	if err != nil {
+		return 0, 0, err
+	}
+	mlat, mlon := dp.rotate(lat, lon, false)
+	return mlat, mlon, nil
+}
+
+// GeomagneticToGeographic converts centred dipole geomagnetic latitude and longitude (decimal degrees)
+// for the decimal `date` back into geocentric latitude and longitude, see `GeographicToGeomagnetic`.
+func (igd *IGRFdata) GeomagneticToGeographic(mlat, mlon, date float64) (float64, float64, error) {
+	if err := checkInitialConditions(mlat, mlon, 0); err != nil {
+		return 0, 0, err
+	}
+	dp, err := igd.dipole(date)
+	if err != nil {
+		return 0, 0, err
+	}
+	lat, lon := dp.rotate(mlat, mlon, true)
+	return lat, lon, nil
+}
@@ -0,0 +1,62 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_GeomagneticPole(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		date    float64
+		wantLat float64
+		wantLon float64
+	}{
+		// positions computed by hand from g10, g11 and h11 of DGRF 2020 and IGRF 1900
+		{name: "2020", date: 2020.0, wantLat: 80.587, wantLon: -72.677},
+		{name: "1900", date: 1900.0, wantLat: 78.614, wantLon: -68.792},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, lon, err := igrf_data.GeomagneticPole(tt.date)
+			if err != nil {
+				t.Fatalf("GeomagneticPole() error = %v", err)
+			}
+			if math.Abs(lat-tt.wantLat) > 0.001 || math.Abs(lon-tt.wantLon) > 0.001 {
+				t.Errorf("GeomagneticPole() = %v, %v, want %v, %v", lat, lon, tt.wantLat, tt.wantLon)
+			}
+			mlat, _, _ := igrf_data.GeographicToGeomagnetic(lat, lon, tt.date)
+			if math.Abs(mlat-90) > 1e-6 {
+				t.Errorf("GeographicToGeomagnetic() of the pole = %v, want 90", mlat)
+			}
+		})
+	}
+}
+
+func TestIGRFdata_GeographicToGeomagnetic(t *testing.T) {
+	igrf_data := New()
+	_, _, err := igrf_data.GeographicToGeomagnetic(100, 0, 2020.0)
+	if err == nil {
+		t.Errorf("GeographicToGeomagnetic() error = %v, want error", err)
+	}
+	// the geographic South pole is on the zero geomagnetic meridian
+	pole_lat, _, _ := igrf_data.GeomagneticPole(2020.0)
+	mlat, mlon, _ := igrf_data.GeographicToGeomagnetic(-90, 0, 2020.0)
+	if math.Abs(mlon) > 1e-6 || math.Abs(mlat+pole_lat) > 1e-6 {
+		t.Errorf("GeographicToGeomagnetic() of the South pole = %v, %v, want %v, 0", mlat, mlon, -pole_lat)
+	}
+	for _, point := range []Point{{59.9, 39.9, 0}, {-33.9, 151.2, 0}, {0, -179.5, 0}, {89.99, 10, 0}} {
+		mlat, mlon, err := igrf_data.GeographicToGeomagnetic(point.Lat, point.Lon, 2021.5)
+		if err != nil {
+			t.Fatalf("GeographicToGeomagnetic() error = %v", err)
+		}
+		lat, lon, err := igrf_data.GeomagneticToGeographic(mlat, mlon, 2021.5)
+		if err != nil {
+			t.Fatalf("GeomagneticToGeographic() error = %v", err)
+		}
+		if math.Abs(lat-point.Lat) > 1e-9 || math.Abs(lon-point.Lon) > 1e-7 {
+			t.Errorf("GeomagneticToGeographic(GeographicToGeomagnetic(%v, %v)) = %v, %v", point.Lat, point.Lon, lat, lon)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package igrf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// TIFF field types
+const (
+	tiff_ascii  = 2
+	tiff_short  = 3
+	tiff_long   = 4
+	tiff_double = 12
+)
+
+// an entry of the TIFF image file directory, `data` is little-endian values
+type tiffEntry struct {
+	tag   uint16
+	kind  uint16
+	count uint32
+	data  []byte
+}
+
+// WriteGridGeoTIFF writes values of the `component` at the altitude with index `alt_index` of the grid `res`
+// as a single-band Float32 GeoTIFF to `w`.
+//
+// The raster is georeferenced in WGS84 longitude/latitude (EPSG:4326), pixels are grid points (PixelIsPoint),
+// the first row is the northernmost latitude. The grid must have at least two latitudes and longitudes with
+// regular steps, NaN values are marked as no data.
+func WriteGridGeoTIFF(w io.Writer, res *GridResults, component Component, alt_index int) error {
+	if res == nil {
+		return errors.New("grid results are missing")
+	}
+	if !component.valid() {
+		return fmt.Errorf("unknown component %v", component)
+	}
+	if alt_index < 0 || alt_index >= len(res.Alts) {
+		return fmt.Errorf("altitude index %v is out of range (0, %v)", alt_index, len(res.Alts)-1)
+	}
+	lat_step, err := regularStep("latitude", res.Lats)
+	if err != nil {
+		return err
+	}
+	lon_step, err := regularStep("longitude", res.Lons)
+	if err != nil {
+		return err
+	}
+	width, height := len(res.Lons), len(res.Lats)
+
+	pixels := new(bytes.Buffer)
+	for row := 0; row < height; row++ {
+		lat_index := height - 1 - row
+		for lon_index := 0; lon_index < width; lon_index++ {
+			value := float32(component.Value(res.At(alt_index, lat_index, lon_index)))
+			binary.Write(pixels, binary.LittleEndian, value)
+		}
+	}
+	entries := []tiffEntry{
+		tiffLong(256, uint32(width)),
+		tiffLong(257, uint32(height)),
+		tiffShort(258, 32),
+		// no compression
+		tiffShort(259, 1),
+		// min-is-black
+		tiffShort(262, 1),
+		// strip offsets, set when the layout is known
+		tiffLong(273, 0),
+		tiffShort(277, 1),
+		tiffLong(278, uint32(height)),
+		tiffLong(279, uint32(pixels.Len())),
+		// chunky
+		tiffShort(284, 1),
+		// IEEE floating point
+		tiffShort(339, 3),
+		// ModelPixelScaleTag
+		tiffDouble(33550, lon_step, lat_step, 0),
+		// ModelTiepointTag: the top left pixel is the first longitude and the last latitude
+		tiffDouble(33922, 0, 0, 0, res.Lons[0], res.Lats[height-1], 0),
+		// GeoKeyDirectoryTag: version 1.1.0 and 4 keys
+		tiffShort(34735,
+			1, 1, 0, 4,
+			// GTModelTypeGeoKey: geographic
+			1024, 0, 1, 2,
+			// GTRasterTypeGeoKey: PixelIsPoint
+			1025, 0, 1, 2,
+			// GeographicTypeGeoKey: WGS84
+			2048, 0, 1, 4326,
+			// GeogAngularUnitsGeoKey: degree
+			2054, 0, 1, 9102,
+		),
+		// GDAL_NODATA
+		{tag: 42113, kind: tiff_ascii, count: 4, data: []byte("nan\x00")},
+	}
+	return writeTIFF(w, entries, pixels.Bytes())
+}
+
+// Returns the step of the `axis`, an error if there are less than two values or steps differ.
+func regularStep(name string, axis []float64) (float64, error) {
+	if len(axis) < 2 {
+		return 0, fmt.Errorf("%v axis must have at least two values", name)
+	}
+	step := axis[1] - axis[0]
+	for index := 2; index < len(axis); index++ {
+		if math.Abs(axis[index]-axis[index-1]-step) > 1e-9*math.Max(1, math.Abs(step)) {
+			return 0, fmt.Errorf("%v step is not regular at %v", name, axis[index])
+		}
+	}
+	return step, nil
+}
+
+// Writes the little-endian TIFF with a single image file directory of `entries` and a single strip of `pixels`.
+//
+// Layout: the header, the directory, values that don't fit into entries, pixels.
+func writeTIFF(w io.Writer, entries []tiffEntry, pixels []byte) error {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+	ifd_offset := uint32(8)
+	extra_offset := ifd_offset + 2 + 12*uint32(len(entries)) + 4
+	pixels_offset := extra_offset
+	for _, entry := range entries {
+		if len(entry.data) > 4 {
+			pixels_offset += uint32(len(entry.data) + len(entry.data)%2)
+		}
+	}
+	for index := range entries {
+		if entries[index].tag == 273 {
+			entries[index] = tiffLong(273, pixels_offset)
+		}
+	}
+
+	out := new(bytes.Buffer)
+	out.WriteString("II")
+	binary.Write(out, binary.LittleEndian, uint16(42))
+	binary.Write(out, binary.LittleEndian, ifd_offset)
+	binary.Write(out, binary.LittleEndian, uint16(len(entries)))
+	extra := new(bytes.Buffer)
+	for _, entry := range entries {
+		binary.Write(out, binary.LittleEndian, entry.tag)
+		binary.Write(out, binary.LittleEndian, entry.kind)
+		binary.Write(out, binary.LittleEndian, entry.count)
+		if len(entry.data) <= 4 {
+			// values are left-justified within 4 bytes
+			value := make([]byte, 4)
+			copy(value, entry.data)
+			out.Write(value)
+			continue
+		}
+		binary.Write(out, binary.LittleEndian, extra_offset+uint32(extra.Len()))
+		extra.Write(entry.data)
+		// values start on a word boundary
+		if len(entry.data)%2 != 0 {
+			extra.WriteByte(0)
+		}
+	}
+	// no next directory
+	binary.Write(out, binary.LittleEndian, uint32(0))
+	out.Write(extra.Bytes())
+	out.Write(pixels)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func tiffShort(tag uint16, values ...uint16) tiffEntry {
+	data := new(bytes.Buffer)
+	binary.Write(data, binary.LittleEndian, values)
+	return tiffEntry{tag: tag, kind: tiff_short, count: uint32(len(values)), data: data.Bytes()}
+}
+
+func tiffLong(tag uint16, value uint32) tiffEntry {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, value)
+	return tiffEntry{tag: tag, kind: tiff_long, count: 1, data: data}
+}
+
+func tiffDouble(tag uint16, values ...float64) tiffEntry {
+	data := new(bytes.Buffer)
+	binary.Write(data, binary.LittleEndian, values)
+	return tiffEntry{tag: tag, kind: tiff_double, count: uint32(len(values)), data: data.Bytes()}
+}
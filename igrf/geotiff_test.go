@@ -0,0 +1,121 @@
+package igrf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Returns raw values of entries of the first image file directory of the little-endian TIFF `data` by tags.
+func readTIFFEntries(t *testing.T, data []byte) map[uint16][]byte {
+	t.Helper()
+	if string(data[:2]) != "II" || binary.LittleEndian.Uint16(data[2:]) != 42 {
+		t.Fatalf("invalid TIFF header %v", data[:4])
+	}
+	sizes := map[uint16]uint32{tiff_ascii: 1, tiff_short: 2, tiff_long: 4, tiff_double: 8}
+	offset := binary.LittleEndian.Uint32(data[4:])
+	count := binary.LittleEndian.Uint16(data[offset:])
+	entries := make(map[uint16][]byte)
+	var prev uint16
+	for index := uint32(0); index < uint32(count); index++ {
+		entry := data[offset+2+12*index:]
+		tag, kind, values := binary.LittleEndian.Uint16(entry), binary.LittleEndian.Uint16(entry[2:]), binary.LittleEndian.Uint32(entry[4:])
+		if tag <= prev {
+			t.Errorf("TIFF tag %v follows %v", tag, prev)
+		}
+		prev = tag
+		size := sizes[kind] * values
+		if size <= 4 {
+			entries[tag] = entry[8 : 8+size]
+			continue
+		}
+		start := binary.LittleEndian.Uint32(entry[8:])
+		entries[tag] = data[start : start+size]
+	}
+	return entries
+}
+
+func TestWriteGridGeoTIFF(t *testing.T) {
+	igrf_data := New()
+	res, err := igrf_data.Grid(GridSpec{LatMin: -10, LatMax: 20, LatStep: 10, LonMin: 100, LonMax: 140, LonStep: 20, AltMin: 0, AltMax: 100, AltStep: 100, Date: 2020.0})
+	if err != nil {
+		t.Fatalf("Grid() error = %v", err)
+	}
+	// a hole in the data
+	res.Values[res.index(1, 0, 2)].Declination = math.NaN()
+	var buf bytes.Buffer
+	if err := WriteGridGeoTIFF(&buf, res, Declination, 1); err != nil {
+		t.Fatalf("WriteGridGeoTIFF() error = %v", err)
+	}
+	data := buf.Bytes()
+	entries := readTIFFEntries(t, data)
+	uint32s := map[uint16]uint32{256: 3, 257: 4, 278: 4, 279: 48}
+	for tag, want := range uint32s {
+		if got := binary.LittleEndian.Uint32(entries[tag]); got != want {
+			t.Errorf("WriteGridGeoTIFF() tag %v = %v, want %v", tag, got, want)
+		}
+	}
+	uint16s := map[uint16]uint16{258: 32, 259: 1, 277: 1, 339: 3}
+	for tag, want := range uint16s {
+		if got := binary.LittleEndian.Uint16(entries[tag]); got != want {
+			t.Errorf("WriteGridGeoTIFF() tag %v = %v, want %v", tag, got, want)
+		}
+	}
+	doubles := func(tag uint16) []float64 {
+		values := make([]float64, len(entries[tag])/8)
+		binary.Read(bytes.NewReader(entries[tag]), binary.LittleEndian, values)
+		return values
+	}
+	if got, want := doubles(33550), []float64{20, 10, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteGridGeoTIFF() pixel scale = %v, want %v", got, want)
+	}
+	if got, want := doubles(33922), []float64{0, 0, 0, 100, 20, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteGridGeoTIFF() tiepoint = %v, want %v", got, want)
+	}
+	keys := make([]uint16, len(entries[34735])/2)
+	binary.Read(bytes.NewReader(entries[34735]), binary.LittleEndian, keys)
+	if keys[3] != 4 || keys[12] != 2048 || keys[15] != 4326 {
+		t.Errorf("WriteGridGeoTIFF() geo keys = %v, want EPSG:4326", keys)
+	}
+	// pixels are rows from north to south
+	offset := binary.LittleEndian.Uint32(entries[273])
+	pixels := make([]float32, 12)
+	binary.Read(bytes.NewReader(data[offset:]), binary.LittleEndian, pixels)
+	for row := 0; row < 4; row++ {
+		for column := 0; column < 3; column++ {
+			want := float32(res.At(1, 3-row, column).Declination)
+			got := pixels[row*3+column]
+			if got != want && !(math.IsNaN(float64(got)) && math.IsNaN(float64(want))) {
+				t.Errorf("WriteGridGeoTIFF() pixel (%v, %v) = %v, want %v", row, column, got, want)
+			}
+		}
+	}
+	if !math.IsNaN(float64(pixels[11])) {
+		t.Errorf("WriteGridGeoTIFF() pixel of no data = %v, want NaN", pixels[11])
+	}
+}
+
+func TestWriteGridGeoTIFFErrors(t *testing.T) {
+	res := &GridResults{Lats: []float64{0, 1, 3}, Lons: []float64{0, 1}, Alts: []float64{0}, Values: make([]IGRFresults, 6)}
+	tests := []struct {
+		name      string
+		res       *GridResults
+		component Component
+		alt_index int
+	}{
+		{name: "No results", res: nil, component: Declination},
+		{name: "Unknown component", res: res, component: Component(100)},
+		{name: "Altitude index", res: res, component: Declination, alt_index: 1},
+		{name: "Irregular latitudes", res: res, component: Declination},
+		{name: "Single longitude", res: &GridResults{Lats: []float64{0, 1}, Lons: []float64{0}, Alts: []float64{0}, Values: make([]IGRFresults, 2)}, component: Declination},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := WriteGridGeoTIFF(&bytes.Buffer{}, tt.res, tt.component, tt.alt_index); err == nil {
+				t.Errorf("WriteGridGeoTIFF() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package igrf
+
+import "github.com/proway2/go-igrf/calc"
+
+// Gradient computes the spatial gradient tensor of the geomagnetic field in nT/km for a given set of coordinates and date,
+// see `IGRF` for valid values.
+//
+// The tensor is in the local geodetic frame (north, east, down): element [i][j] is the derivative of
+// the i-th component (X, Y, Z) along the j-th axis. It is symmetric and its trace is zero.
+func (igd *IGRFdata) Gradient(lat, lon, alt, date float64) ([3][3]float64, error) {
+	if err := checkInitialConditions(lat, lon, alt); err != nil {
+		return [3][3]float64{}, err
+	}
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return [3][3]float64{}, err
+	}
+	_, grad := calc.Gradient(lat, lon, alt, set.nmax, set.main)
+	return grad, nil
+}
@@ -0,0 +1,47 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_Gradient(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		lat     float64
+		lon     float64
+		alt     float64
+		wantErr bool
+	}{
+		{name: "Mid latitude", lat: 59.9, lon: 39.9, alt: 10.0},
+		{name: "Southern hemisphere", lat: -45.5, lon: -120.3, alt: 300.0},
+		{name: "Altitude out of range", lat: 0.0, lon: 0.0, alt: 1000.0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.Gradient(tt.lat, tt.lon, tt.alt, 2021.5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Gradient() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			// the downward derivative is the central difference along altitude, the frame doesn't rotate along it
+			step := 0.1
+			upper, _ := igrf_data.IGRF(tt.lat, tt.lon, tt.alt+step, 2021.5)
+			lower, _ := igrf_data.IGRF(tt.lat, tt.lon, tt.alt-step, 2021.5)
+			want := []float64{
+				-(upper.NorthComponent - lower.NorthComponent) / (2 * step),
+				-(upper.EastComponent - lower.EastComponent) / (2 * step),
+				-(upper.VerticalComponent - lower.VerticalComponent) / (2 * step),
+			}
+			for i := range want {
+				if math.Abs(got[i][2]-want[i]) > 1e-3 {
+					t.Errorf("Gradient() [%v][2] = %v, want %v", i, got[i][2], want[i])
+				}
+			}
+		})
+	}
+}
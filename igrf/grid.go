@@ -0,0 +1,135 @@
+package igrf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/proway2/go-igrf/calc"
+)
+
+// GridSpec defines a regular grid of points for `Grid`.
+//
+// Latitudes are LatMin, LatMin+LatStep, ... up to LatMax inclusive, the same is for longitudes and altitudes.
+// If AltStep is 0, the grid has a single altitude AltMin. See `IGRF` for valid values.
+type GridSpec struct {
+	LatMin, LatMax, LatStep float64
+	LonMin, LonMax, LonStep float64
+	AltMin, AltMax, AltStep float64
+	// decimal date
+	Date float64
+	// number of concurrent workers, runtime.NumCPU() if not positive
+	Workers int
+}
+
+// GridResults holds results of `Grid` in a dense array.
+type GridResults struct {
+	Lats []float64
+	Lons []float64
+	Alts []float64
+	// results ordered by altitude, then by latitude, then by longitude, see `At`
+	Values []IGRFresults
+}
+
+// At returns the result for the altitude, latitude and longitude with the given indices.
+func (gr *GridResults) At(alt_index, lat_index, lon_index int) IGRFresults {
+	return gr.Values[gr.index(alt_index, lat_index, lon_index)]
+}
+
+// Returns the position of the result in `Values`.
+func (gr *GridResults) index(alt_index, lat_index, lon_index int) int {
+	return (alt_index*len(gr.Lats)+lat_index)*len(gr.Lons) + lon_index
+}
+
+// Grid computes values for the geomagnetic field and secular variation for every point of the grid.
+//
+// Rows of the grid (points of the same latitude and altitude) are spread across workers.
+// Coeffs are interpolated once for the grid, terms of the spherical harmonic sums that depend on the latitude
+// are computed once per row and terms that depend on the longitude are computed once per grid.
+func (igd *IGRFdata) Grid(spec GridSpec) (*GridResults, error) {
+	if igd.shc == nil {
+		return nil, errors.New("IGRFdata structure is not initialized")
+	}
+	lats, err := gridAxis("latitude", spec.LatMin, spec.LatMax, spec.LatStep)
+	if err != nil {
+		return nil, err
+	}
+	lons, err := gridAxis("longitude", spec.LonMin, spec.LonMax, spec.LonStep)
+	if err != nil {
+		return nil, err
+	}
+	alts := []float64{spec.AltMin}
+	if spec.AltStep != 0 {
+		alts, err = gridAxis("altitude", spec.AltMin, spec.AltMax, spec.AltStep)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the grid is checked by its corners, all axes are monotonic
+	for _, alt := range []float64{alts[0], alts[len(alts)-1]} {
+		for _, lat := range []float64{lats[0], lats[len(lats)-1]} {
+			for _, lon := range []float64{lons[0], lons[len(lons)-1]} {
+				if err := checkInitialConditions(lat, lon, alt); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	set, err := igd.coeffsSet(spec.Date)
+	if err != nil {
+		return nil, err
+	}
+	nmax := set.nmax
+	lon_terms := make([]*calc.LongitudeTerms, len(lons))
+	for index, lon := range lons {
+		lon_terms[index] = calc.NewLongitudeTerms(lon, nmax)
+	}
+	res := &GridResults{Lats: lats, Lons: lons, Alts: alts, Values: make([]IGRFresults, len(alts)*len(lats)*len(lons))}
+
+	workers := spec.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ws := calc.NewWorkspace(nmax)
+			for row := range rows {
+				alt_index, lat_index := row/len(lats), row%len(lats)
+				ws.SetLatitude(lats[lat_index], alts[alt_index], nmax)
+				for lon_index := range lons {
+					ws.SetLongitudeTerms(lon_terms[lon_index])
+					res.Values[res.index(alt_index, lat_index, lon_index)] = set.results(ws)
+				}
+			}
+		}()
+	}
+	for row := 0; row < len(alts)*len(lats); row++ {
+		rows <- row
+	}
+	close(rows)
+	wg.Wait()
+	return res, nil
+}
+
+// Returns values from `min` to `max` inclusive with the `step`.
+func gridAxis(name string, min, max, step float64) ([]float64, error) {
+	if step <= 0 || math.IsNaN(step) || math.IsInf(step, 0) {
+		return nil, fmt.Errorf("%v step %v must be positive", name, step)
+	}
+	if max < min {
+		return nil, fmt.Errorf("%v range (%v, %v) is incorrect", name, min, max)
+	}
+	// a small tolerance keeps `max` when the range is a multiple of the step
+	count := int(math.Floor((max-min)/step+1e-9)) + 1
+	axis := make([]float64, count)
+	for index := range axis {
+		axis[index] = math.Min(min+float64(index)*step, max)
+	}
+	return axis, nil
+}
@@ -0,0 +1,84 @@
+package igrf
+
+import (
+	"testing"
+)
+
+func TestIGRFdata_Grid(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name     string
+		spec     GridSpec
+		wantLats int
+		wantLons int
+		wantAlts int
+		wantErr  bool
+	}{
+		{
+			name:     "2D grid",
+			spec:     GridSpec{LatMin: -90, LatMax: 90, LatStep: 15, LonMin: -180, LonMax: 180, LonStep: 30, AltMin: 0.5, Date: 2021.5},
+			wantLats: 13,
+			wantLons: 13,
+			wantAlts: 1,
+		},
+		{
+			name:     "3D grid with a single worker",
+			spec:     GridSpec{LatMin: 40, LatMax: 60, LatStep: 7, LonMin: 30, LonMax: 40, LonStep: 2.5, AltMin: 0, AltMax: 600, AltStep: 300, Date: 1955.3, Workers: 1},
+			wantLats: 3,
+			wantLons: 5,
+			wantAlts: 3,
+		},
+		{
+			name:     "Steps not multiple of the range",
+			spec:     GridSpec{LatMin: -0.3, LatMax: 0.3, LatStep: 0.1, LonMin: 179.9, LonMax: 180, LonStep: 0.1, Date: 2029.5},
+			wantLats: 7,
+			wantLons: 2,
+			wantAlts: 1,
+		},
+		{
+			name:    "Zero step",
+			spec:    GridSpec{LatMin: 40, LatMax: 60, LonMin: 30, LonMax: 40, LonStep: 2.5, Date: 2020},
+			wantErr: true,
+		},
+		{
+			name:    "Reversed range",
+			spec:    GridSpec{LatMin: 60, LatMax: 40, LatStep: 1, LonMin: 30, LonMax: 40, LonStep: 2.5, Date: 2020},
+			wantErr: true,
+		},
+		{
+			name:    "Latitude out of range",
+			spec:    GridSpec{LatMin: -95, LatMax: 40, LatStep: 1, LonMin: 30, LonMax: 40, LonStep: 2.5, Date: 2020},
+			wantErr: true,
+		},
+		{
+			name:    "Date out of range",
+			spec:    GridSpec{LatMin: 40, LatMax: 60, LatStep: 1, LonMin: 30, LonMax: 40, LonStep: 2.5, Date: 1800},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.Grid(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Grid() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Lats) != tt.wantLats || len(got.Lons) != tt.wantLons || len(got.Alts) != tt.wantAlts {
+				t.Fatalf("Grid() size = %vx%vx%v, want %vx%vx%v", len(got.Alts), len(got.Lats), len(got.Lons), tt.wantAlts, tt.wantLats, tt.wantLons)
+			}
+			for alt_index, alt := range got.Alts {
+				for lat_index, lat := range got.Lats {
+					for lon_index, lon := range got.Lons {
+						want, _ := igrf_data.IGRF(lat, lon, alt, tt.spec.Date)
+						if value := got.At(alt_index, lat_index, lon_index); value != want {
+							t.Errorf("Grid() at (%v, %v, %v) = %v, want %v", lat, lon, alt, value, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package igrf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/proway2/go-igrf/coeffs"
+)
+
+// Returns the model of the axial centred dipole.
+func newAxialDipole(t *testing.T) *IGRFdata {
+	t.Helper()
+	raw := `c/s deg ord IGRF IGRF SV
+g/h n m 2000.0 2005.0 2005-10
+g  1  0 -30000.0 -30000.0 0.0
+g  1  1      0.0      0.0 0.0
+h  1  1      0.0      0.0 0.0
+`
+	shc, err := coeffs.Load(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	dipole, _ := NewFromCoeffs(shc)
+	return dipole
+}
+
+// Checks the error returned by the function `name` against `wantErr`,
+// returns whether the test case goes on, i.e. there is no error and it's expected.
+func checkError(t *testing.T, name string, err error, wantErr bool) bool {
+	t.Helper()
+	if (err != nil) != wantErr {
+		t.Errorf("%v() error = %v, wantErr %v", name, err, wantErr)
+		return false
+	}
+	return err == nil
+}
@@ -5,13 +5,15 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"time"
 
 	"github.com/proway2/go-igrf/calc"
 	"github.com/proway2/go-igrf/coeffs"
 )
 
 type IGRFdata struct {
-	shc *coeffs.IGRFcoeffs
+	shc     *coeffs.IGRFcoeffs
+	sv_mode SVMode
 }
 
 // New returns an initialized IGRF structure that could be used to compute .
@@ -23,6 +25,41 @@ func New() *IGRFdata {
 	return &IGRFdata{shc: shc}
 }
 
+// NewFromCoeffs returns an IGRF structure that computes values using the given set of coeffs,
+// e.g. loaded by `coeffs.Load` or `coeffs.LoadFile`, or the World Magnetic Model loaded by `coeffs.LoadCOFFile`.
+func NewFromCoeffs(shc *coeffs.IGRFcoeffs) (*IGRFdata, error) {
+	if shc == nil {
+		return nil, errors.New("coeffs are not initialized")
+	}
+	return &IGRFdata{shc: shc}, nil
+}
+
+// NewWithGeneration returns an IGRF structure that computes values using the embedded coeffs
+// of the given IGRF `generation`, see `coeffs.Generations` for available ones.
+func NewWithGeneration(generation int) (*IGRFdata, error) {
+	shc, err := coeffs.NewCoeffsDataForGeneration(generation)
+	if err != nil {
+		return nil, err
+	}
+	return &IGRFdata{shc: shc}, nil
+}
+
+// Generation returns the IGRF generation of the coeffs in use, or 0 for the loaded coeffs.
+func (igd *IGRFdata) Generation() int {
+	if igd.shc == nil {
+		return 0
+	}
+	return igd.shc.Generation()
+}
+
+// ValidRange returns the range of dates (decimal years) covered by the coeffs in use.
+func (igd *IGRFdata) ValidRange() (float64, float64) {
+	if igd.shc == nil {
+		return 0, 0
+	}
+	return igd.shc.ValidRange()
+}
+
 // IGRF computes values for the geomagnetic field and secular variation for a given set of coordinates and date
 // and returns a populated `IGRFresults` structure.
 //
@@ -31,7 +68,7 @@ func New() *IGRFdata {
 //
 // alt - geodetic altitude above mean sea level in km (-1.00 to 600.00).
 //
-// date - decimal date (1900.00 to 2025).
+// date - decimal date, see `ValidRange` (1900.00 to 2030.00 for IGRF-14).
 func (igd *IGRFdata) IGRF(lat, lon, alt, date float64) (IGRFresults, error) {
 	if igd.shc == nil {
 		return IGRFresults{}, errors.New("IGRFdata structure is not initialized")
@@ -39,11 +76,19 @@ func (igd *IGRFdata) IGRF(lat, lon, alt, date float64) (IGRFresults, error) {
 	if err := checkInitialConditions(lat, lon, alt); err != nil {
 		return IGRFresults{}, err
 	}
-	start_coeffs, end_coeffs, nmax, err := igd.shc.Coeffs(date)
+	set, err := igd.coeffsSet(date)
 	if err != nil {
 		return IGRFresults{}, err
 	}
-	x, y, z, xtemp, ytemp, ztemp := calc.Shval3(lat, lon, alt, nmax, start_coeffs, end_coeffs)
+	ws := calc.NewWorkspace(set.nmax)
+	ws.SetLatitude(lat, alt, set.nmax)
+	ws.SetLongitude(lon, set.nmax)
+	return set.results(ws), nil
+}
+
+// Populates `IGRFresults` from X, Y, Z for the requested date (x, y, z) and for one year later (xtemp, ytemp, ztemp),
+// i.e. SV is computed as the finite difference.
+func newResults(x, y, z, xtemp, ytemp, ztemp float64) IGRFresults {
 	d, i, h, f := calc.Dihf(x, y, z)
 
 	dtemp, itemp, htemp, ftemp := calc.Dihf(xtemp, ytemp, ztemp)
@@ -107,7 +152,7 @@ func (igd *IGRFdata) IGRF(lat, lon, alt, date float64) (IGRFresults, error) {
 	// 	/* while rest is ok */
 	// }
 
-	res := IGRFresults{
+	return IGRFresults{
 		Declination:         d,
 		DeclinationSV:       ddot,
 		Inclination:         i,
@@ -123,7 +168,12 @@ func (igd *IGRFdata) IGRF(lat, lon, alt, date float64) (IGRFresults, error) {
 		TotalIntensity:      f,
 		TotalSV:             fdot,
 	}
-	return res, nil
+}
+
+// IGRFAt computes values for the geomagnetic field and secular variation for a given set of coordinates and time `t`,
+// which is converted into decimal date by `coeffs.DecimalYear`. See `IGRF` for valid values.
+func (igd *IGRFdata) IGRFAt(lat, lon, alt float64, t time.Time) (IGRFresults, error) {
+	return igd.IGRF(lat, lon, alt, coeffs.DecimalYear(t))
 }
 
 func checkInitialConditions(lat, lon, alt float64) error {
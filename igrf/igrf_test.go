@@ -61,6 +61,119 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewFromCoeffs(t *testing.T) {
+	shc, _ := coeffs.NewCoeffsData()
+	tests := []struct {
+		name    string
+		shc     *coeffs.IGRFcoeffs
+		want    *IGRFdata
+		wantErr bool
+	}{
+		{
+			name:    "Creating a IGRF data structure from coeffs",
+			shc:     shc,
+			want:    &IGRFdata{shc: shc},
+			wantErr: false,
+		},
+		{
+			name:    "No coeffs",
+			shc:     nil,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFromCoeffs(tt.shc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromCoeffs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFromCoeffs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromCoeffsHighDegree(t *testing.T) {
+	// the main field and SV of IGRF-14 for 2025.0 extended to the degree 133 like WMMHR
+	igrf_data := New()
+	shc, err := coeffs.LoadCOFFile(dir_path + "/IGRF14-HR.COF")
+	if err != nil {
+		t.Fatalf("LoadCOFFile() error = %v", err)
+	}
+	wmmhr, _ := NewFromCoeffs(shc)
+	for _, point := range []Point{{59.9, 39.9, 0}, {-33.3, 170, 300}} {
+		got, err := wmmhr.IGRF(point.Lat, point.Lon, point.Alt, 2027.5)
+		if err != nil {
+			t.Fatalf("IGRF() error = %v", err)
+		}
+		want, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, 2025.0)
+		// small terms of high degrees change the field a bit
+		if math.IsNaN(got.TotalIntensity) || math.Abs(got.TotalIntensity-want.TotalIntensity) > 500 {
+			t.Errorf("IGRF() at %v total intensity = %v, want about %v", point, got.TotalIntensity, want.TotalIntensity)
+		}
+		// SV is linear, so the yearly difference is the same as the analytic SV
+		fd, _ := wmmhr.WithSVMode(FiniteDifferenceSV).IGRF(point.Lat, point.Lon, point.Alt, 2027.5)
+		if math.Abs(fd.NorthSV-got.NorthSV) > 1e-6 || math.Abs(fd.EastSV-got.EastSV) > 1e-6 || math.Abs(fd.VerticalSV-got.VerticalSV) > 1e-6 {
+			t.Errorf("IGRF() at %v SV = (%v, %v, %v), yearly difference (%v, %v, %v)", point, got.NorthSV, got.EastSV, got.VerticalSV, fd.NorthSV, fd.EastSV, fd.VerticalSV)
+		}
+	}
+}
+
+func TestNewWithGeneration(t *testing.T) {
+	tests := []struct {
+		name      string
+		gen       int
+		wantStart float64
+		wantEnd   float64
+		// g(1,0) of the last main field epoch as published in the coeffs table of the generation
+		epoch   float64
+		wantG10 float64
+		wantErr bool
+	}{
+		{name: "IGRF-11", gen: 11, wantStart: 1900.0, wantEnd: 2015.0, epoch: 2010.0, wantG10: -29496.5},
+		{name: "IGRF-12", gen: 12, wantStart: 1900.0, wantEnd: 2020.0, epoch: 2015.0, wantG10: -29442.0},
+		{name: "IGRF-13", gen: 13, wantStart: 1900.0, wantEnd: 2025.0, epoch: 2020.0, wantG10: -29404.8},
+		{name: "IGRF-14", gen: 14, wantStart: 1900.0, wantEnd: 2030.0, epoch: 2025.0, wantG10: -29350.0},
+		{name: "Not available generation", gen: 10, wantErr: true},
+	}
+	available := make(map[int]bool)
+	for _, generation := range coeffs.Generations() {
+		available[generation] = true
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.wantErr && !available[tt.gen] {
+				t.Skipf("the coeffs table of IGRF-%v is not embedded", tt.gen)
+			}
+			got, err := NewWithGeneration(tt.gen)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewWithGeneration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Generation() != tt.gen {
+				t.Errorf("Generation() = %v, want %v", got.Generation(), tt.gen)
+			}
+			start, end := got.ValidRange()
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ValidRange() = (%v, %v), want (%v, %v)", start, end, tt.wantStart, tt.wantEnd)
+			}
+			gh, _, _, err := got.shc.Coeffs(tt.epoch)
+			if err != nil {
+				t.Fatalf("Coeffs(%v) error = %v", tt.epoch, err)
+			}
+			if math.Abs((*gh)[0]-tt.wantG10) > 1e-6 {
+				t.Errorf("g(1,0) at %v = %v, want %v", tt.epoch, (*gh)[0], tt.wantG10)
+			}
+		})
+	}
+}
+
 // SV fields are just integers in FORTRAN, so there might be situations where:
 // calculated value 16.47, reference 17
 // calculated value 2.52, reference 3
@@ -0,0 +1,142 @@
+package igrf
+
+import (
+	"math"
+)
+
+const (
+	// the maximal tracing step relative to the distance from the Earth's centre for the integral invariant
+	lshell_max_step_ratio = 0.005
+	// coeffs of the approximation of L by the integral invariant, see Hilton (1971)
+	hilton_a1 = 1.35047
+	hilton_a2 = 0.465376
+	hilton_a3 = 0.0475455
+)
+
+// LShellResults represents McIlwain L-shell parameter.
+//
+// L - the L parameter in Earth radii (6371.2 km).
+//
+// BB0 - the ratio of the field at the point to the minimal field along the field line (at the magnetic equator).
+//
+// Dipole - L and BB0 are computed for the centred dipole, see `DipoleLShell`.
+type LShellResults struct {
+	L      float64
+	BB0    float64
+	Dipole bool
+}
+
+// LShell computes McIlwain L parameter and B/B0 at the geodetic point `lat`, `lon`, `alt` (km, not less than -1.0)
+// for the decimal `date`.
+//
+// The field line is traced between mirror points, where the field equals the field at the point,
+// to compute the integral invariant, L is derived from it by Hilton (1971) approximation.
+// If the field line doesn't reach the ground, the dipole values are returned, see `DipoleLShell`.
+func (igd *IGRFdata) LShell(lat, lon, alt, date float64) (LShellResults, error) {
+	point := Point{Lat: lat, Lon: lon, Alt: alt}
+	opts := TraceOptions{TargetAlt: -1}
+	if err := checkTraceConditions(point, opts); err != nil {
+		return LShellResults{}, err
+	}
+	dp, err := igd.dipole(date)
+	if err != nil {
+		return LShellResults{}, err
+	}
+	tr, err := igd.newTracer(date, opts)
+	if err != nil {
+		return LShellResults{}, err
+	}
+	tr.max_step_ratio = lshell_max_step_ratio
+	start := toECEF(point)
+	backward, south_found := tr.trace(start, -1)
+	forward, north_found := tr.trace(start, 1)
+	if !south_found || !north_found {
+		return dipoleLShell(dp, lat, lon, alt), nil
+	}
+	b_mirror := magnitude(tr.field(start))
+	b_min := b_mirror
+	var invariant float64
+	for _, points := range [][]Point{backward, forward} {
+		integral, b_equator := mirrorIntegral(tr, points, b_mirror)
+		invariant += integral
+		b_min = math.Min(b_min, b_equator)
+	}
+	// the dipole moment in nT * Re^3 and the integral invariant in Earth radii
+	moment := dp.b0
+	invariant /= earths_radius
+	x := invariant * invariant * invariant * b_mirror / moment
+	cube_root := math.Cbrt(x)
+	l := math.Cbrt(moment / b_mirror * (1 + hilton_a1*cube_root + hilton_a2*cube_root*cube_root + hilton_a3*x))
+	return LShellResults{L: l, BB0: b_mirror / b_min}, nil
+}
+
+// DipoleLShell computes L parameter and B/B0 for the centred dipole, see `GeographicToGeomagnetic`,
+// at the geodetic point `lat`, `lon`, `alt` (km, not less than -1.0) for the decimal `date`.
+func (igd *IGRFdata) DipoleLShell(lat, lon, alt, date float64) (LShellResults, error) {
+	if err := checkTraceConditions(Point{Lat: lat, Lon: lon, Alt: alt}, TraceOptions{TargetAlt: -1}); err != nil {
+		return LShellResults{}, err
+	}
+	dp, err := igd.dipole(date)
+	if err != nil {
+		return LShellResults{}, err
+	}
+	return dipoleLShell(dp, lat, lon, alt), nil
+}
+
+// Computes L and B/B0 of the dipole `dp` at the geodetic point.
+func dipoleLShell(dp centredDipole, lat, lon, alt float64) LShellResults {
+	position := toECEF(Point{Lat: lat, Lon: lon, Alt: alt})
+	radius := magnitude(position)
+	mlat, _ := dp.rotate(rad2deg(math.Asin(position[2]/radius)), lon, false)
+	smlat, cmlat := math.Sincos(mlat * math.Pi / 180)
+	cos2 := cmlat * cmlat
+	return LShellResults{
+		L:      radius / earths_radius / cos2,
+		BB0:    math.Sqrt(1+3*smlat*smlat) / (cos2 * cos2 * cos2),
+		Dipole: true,
+	}
+}
+
+// Integrates sqrt(1 - B/Bm) along the traced `points` from the start up to the mirror point, where B = `b_mirror`,
+// returns the integral in km and the minimal field on the way.
+func mirrorIntegral(tr *tracer, points []Point, b_mirror float64) (float64, float64) {
+	var integral float64
+	b_min := b_mirror
+	previous := toECEF(points[0])
+	u_previous := 0.0
+	for _, point := range points[1:] {
+		position := toECEF(point)
+		b := magnitude(tr.field(position))
+		u := 1 - b/b_mirror
+		integral += sqrtIntegral(u_previous, u, distance(previous, position))
+		if u < 0 {
+			break
+		}
+		b_min = math.Min(b_min, b)
+		previous, u_previous = position, u
+	}
+	return integral, b_min
+}
+
+// Returns the integral of sqrt(u) over the segment of the `length`, where u changes linearly from `u0` to `u1`,
+// only the part where u is positive counts.
+func sqrtIntegral(u0, u1, length float64) float64 {
+	if u0 < 0 && u1 < 0 {
+		return 0
+	}
+	if u0 < 0 || u1 < 0 {
+		// the part of the segment up to the root
+		positive := math.Max(u0, u1)
+		length *= positive / (positive - math.Min(u0, u1))
+		u0, u1 = 0, positive
+	}
+	if u1 == u0 {
+		return length * math.Sqrt(u0)
+	}
+	return length * 2 / 3 * (u1*math.Sqrt(u1) - u0*math.Sqrt(u0)) / (u1 - u0)
+}
+
+// Returns the length of the vector `v`.
+func magnitude(v [3]float64) float64 {
+	return math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+}
@@ -0,0 +1,59 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_LShellDipole(t *testing.T) {
+	// for the dipole field the traced L and B/B0 must be the same as analytical ones
+	dipole := newAxialDipole(t)
+	for _, point := range []Point{{0, 0, 0}, {30, 0, 0}, {45, 30, 1000}, {-60, 30, 5000}, {70, 20, 0}} {
+		got, err := dipole.LShell(point.Lat, point.Lon, point.Alt, 2001.0)
+		if err != nil {
+			t.Fatalf("LShell() error = %v", err)
+		}
+		want, err := dipole.DipoleLShell(point.Lat, point.Lon, point.Alt, 2001.0)
+		if err != nil {
+			t.Fatalf("DipoleLShell() error = %v", err)
+		}
+		if got.Dipole || !want.Dipole {
+			t.Errorf("LShell() Dipole = %v, DipoleLShell() Dipole = %v", got.Dipole, want.Dipole)
+		}
+		if !isClose(got.L, want.L, 2e-4, 0) || !isClose(got.BB0, want.BB0, 1e-4, 0) {
+			t.Errorf("LShell(%v) = %v, want %v", point, got, want)
+		}
+	}
+}
+
+func TestIGRFdata_LShell(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		point   Point
+		wantErr bool
+	}{
+		{name: "Mid latitude", point: Point{45, 30, 1000}},
+		{name: "Auroral zone", point: Point{70, 20, 0}},
+		{name: "Inner belt", point: Point{-30, -40, 1000}},
+		{name: "Below the limit", point: Point{45, 30, -2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.LShell(tt.point.Lat, tt.point.Lon, tt.point.Alt, 2020.0)
+			if !checkError(t, "LShell", err, tt.wantErr) {
+				return
+			}
+			// the conjugate point is on the same field line
+			conjugate, _ := igrf_data.ConjugatePoint(tt.point, 2020.0)
+			other, _ := igrf_data.LShell(conjugate.Conjugate.Lat, conjugate.Conjugate.Lon, conjugate.Conjugate.Alt, 2020.0)
+			if got.BB0 < 1 || other.BB0 < 1 || math.Abs(got.L-other.L)/got.L > 0.02 {
+				t.Errorf("LShell() = %v, at the conjugate point %v", got, other)
+			}
+			// L is close to the distance of the apex in Earth radii
+			if apex := 1 + conjugate.Apex.Alt/earths_radius; got.L < 1 || math.Abs(got.L-apex)/apex > 0.1 {
+				t.Errorf("LShell() L = %v, want close to the apex distance %v", got.L, apex)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package igrf
+
+import (
+	"math"
+	"time"
+
+	"github.com/proway2/go-igrf/coeffs"
+)
+
+// the Julian date of J2000.0 epoch, 2000-01-01 12:00 UTC
+const j2000 = 2451545.0
+
+// SubsolarPoint returns geocentric latitude and longitude (decimal degrees) of the point where the Sun is at zenith
+// at time `t`. The low precision solar coordinates of the Astronomical Almanac are used, the accuracy is about 0.01°.
+func SubsolarPoint(t time.Time) (float64, float64) {
+	// days since J2000.0
+	n := float64(t.UTC().UnixNano())/float64(24*time.Hour) + 2440587.5 - j2000
+	mean_lon := 280.460 + 0.9856474*n
+	anomaly := (357.528 + 0.9856003*n) * math.Pi / 180
+	ecliptic_lon := (mean_lon + 1.915*math.Sin(anomaly) + 0.020*math.Sin(2*anomaly)) * math.Pi / 180
+	obliquity := (23.439 - 0.0000004*n) * math.Pi / 180
+	declination := math.Asin(math.Sin(obliquity) * math.Sin(ecliptic_lon))
+	right_ascension := math.Atan2(math.Cos(obliquity)*math.Sin(ecliptic_lon), math.Cos(ecliptic_lon))
+	// Greenwich mean sidereal time in degrees
+	sidereal := 280.46061837 + 360.98564736629*n
+	return rad2deg(declination), normalizeLongitude(rad2deg(right_ascension) - sidereal)
+}
+
+// MagneticLocalTime returns magnetic local time in hours (0 to 24) at geodetic latitude `lat` and longitude `lon`
+// (decimal degrees) at time `t`, it's defined by the centred dipole longitudes of the point and the subsolar point
+// for the date, see `GeographicToGeomagnetic`: the magnetic noon is on the meridian of the subsolar point.
+func (igd *IGRFdata) MagneticLocalTime(lat, lon float64, t time.Time) (float64, error) {
+	if err := checkInitialConditions(lat, lon, 0); err != nil {
+		return 0, err
+	}
+	dp, err := igd.dipole(coeffs.DecimalYear(t))
+	if err != nil {
+		return 0, err
+	}
+	position := toECEF(Point{Lat: lat, Lon: lon})
+	_, mlon := dp.rotate(rad2deg(math.Asin(position[2]/magnitude(position))), lon, false)
+	return localTime(dp, mlon, t), nil
+}
+
+// QuasiDipoleLocalTime returns magnetic local time in hours (0 to 24) at the geodetic point `lat`, `lon`, `alt` (km)
+// at time `t` defined by the Quasi-Dipole longitude of the point, see `GeodeticToApex`,
+// and the centred dipole longitude of the subsolar point.
+func (igd *IGRFdata) QuasiDipoleLocalTime(lat, lon, alt float64, t time.Time) (float64, error) {
+	coords, err := igd.GeodeticToApex(lat, lon, alt, coeffs.DecimalYear(t), 0)
+	if err != nil {
+		return 0, err
+	}
+	dp, err := igd.dipole(coeffs.DecimalYear(t))
+	if err != nil {
+		return 0, err
+	}
+	return localTime(dp, coords.ApexLon, t), nil
+}
+
+// Returns magnetic local time in hours for the magnetic longitude `mlon` at time `t`.
+func localTime(dp centredDipole, mlon float64, t time.Time) float64 {
+	subsolar_lat, subsolar_lon := SubsolarPoint(t)
+	_, subsolar_mlon := dp.rotate(subsolar_lat, subsolar_lon, false)
+	hours := math.Mod(12+normalizeLongitude(mlon-subsolar_mlon)/15, 24)
+	if hours < 0 {
+		hours += 24
+	}
+	return hours
+}
@@ -0,0 +1,78 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/proway2/go-igrf/coeffs"
+)
+
+func TestSubsolarPoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       string
+		wantLat float64
+		wantLon float64
+	}{
+		// solstice and equinox, the longitude is defined by the equation of time
+		{name: "June solstice", t: "2020-06-20T21:43:00Z", wantLat: 23.436, wantLon: -145.305},
+		{name: "March equinox", t: "2021-03-20T09:37:00Z", wantLat: 0.0, wantLon: 37.608},
+		{name: "Maximal equation of time", t: "2021-11-03T12:00:00Z", wantLat: -15.215, wantLon: -4.111},
+		{name: "Minimal equation of time", t: "2021-02-11T12:00:00Z", wantLat: -13.854, wantLon: 3.551},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moment, _ := time.Parse(time.RFC3339, tt.t)
+			lat, lon := SubsolarPoint(moment)
+			if math.Abs(lat-tt.wantLat) > 0.01 || math.Abs(lon-tt.wantLon) > 0.01 {
+				t.Errorf("SubsolarPoint() = %v, %v, want %v, %v", lat, lon, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
+
+func TestIGRFdata_MagneticLocalTime(t *testing.T) {
+	igrf_data := New()
+	moment, _ := time.Parse(time.RFC3339, "2021-03-20T09:37:00Z")
+	// the magnetic noon is at the subsolar point
+	lat, lon := SubsolarPoint(moment)
+	got, err := igrf_data.MagneticLocalTime(lat, lon, moment)
+	if checkError(t, "MagneticLocalTime", err, false) && math.Abs(got-12) > 0.01 {
+		t.Errorf("MagneticLocalTime() at the subsolar point = %v, want 12", got)
+	}
+	// the time goes by an hour per 15° of magnetic longitude
+	// latitudes are geocentric in the conversion
+	date := coeffs.DecimalYear(moment)
+	e2 := wgs84_f * (2 - wgs84_f)
+	mlat, mlon, _ := igrf_data.GeographicToGeomagnetic(rad2deg(math.Atan((1-e2)*math.Tan(60*math.Pi/180))), 30, date)
+	for _, shift := range []float64{15, 90, 180} {
+		lat, lon, _ := igrf_data.GeomagneticToGeographic(mlat, normalizeLongitude(mlon+shift), date)
+		lat = rad2deg(math.Atan(math.Tan(lat*math.Pi/180) / (1 - e2)))
+		before, _ := igrf_data.MagneticLocalTime(60, 30, moment)
+		after, _ := igrf_data.MagneticLocalTime(lat, lon, moment)
+		if diff := math.Mod(after-before+24, 24); math.Abs(diff-shift/15) > 0.01 {
+			t.Errorf("MagneticLocalTime() difference = %v h, want %v h", diff, shift/15)
+		}
+	}
+	_, err = igrf_data.MagneticLocalTime(95, 0, moment)
+	checkError(t, "MagneticLocalTime", err, true)
+}
+
+func TestIGRFdata_QuasiDipoleLocalTime(t *testing.T) {
+	igrf_data := New()
+	moment, _ := time.Parse(time.RFC3339, "2021-03-20T09:37:00Z")
+	for _, point := range []Point{{60, 30, 0}, {-70, 20, 0}, {0, -100, 0}} {
+		got, err := igrf_data.QuasiDipoleLocalTime(point.Lat, point.Lon, point.Alt, moment)
+		if !checkError(t, "QuasiDipoleLocalTime", err, false) {
+			continue
+		}
+		// Quasi-Dipole longitude differs from the centred dipole one by a few degrees
+		want, _ := igrf_data.MagneticLocalTime(point.Lat, point.Lon, moment)
+		if got < 0 || got >= 24 || math.Abs(got-want) > 1 {
+			t.Errorf("QuasiDipoleLocalTime(%v) = %v, want close to %v", point, got, want)
+		}
+	}
+	_, err := igrf_data.QuasiDipoleLocalTime(60, 30, -2, moment)
+	checkError(t, "QuasiDipoleLocalTime", err, true)
+}
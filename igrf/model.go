@@ -0,0 +1,46 @@
+package igrf
+
+import (
+	"context"
+	"time"
+)
+
+// Point is a location for the field computation.
+//
+// Lat, Lon - geodetic latitude and longitude (WGS84) in decimal degrees.
+//
+// Alt - geodetic altitude above mean sea level in km.
+type Point struct {
+	Lat float64
+	Lon float64
+	Alt float64
+}
+
+// FieldModel is a geomagnetic field model, e.g. IGRF, a loaded custom model or a test fake.
+type FieldModel interface {
+	// Field computes values for the geomagnetic field and secular variation at `point` for time `t`.
+	Field(ctx context.Context, point Point, t time.Time) (IGRFresults, error)
+	// ValidRange returns the range of dates (decimal years) covered by the model.
+	ValidRange() (float64, float64)
+	// Name returns the name of the model, e.g. IGRF-14.
+	Name() string
+}
+
+var _ FieldModel = (*IGRFdata)(nil)
+
+// Field computes values for the geomagnetic field and secular variation at `point` for time `t`,
+// see `IGRF` for valid values.
+func (igd *IGRFdata) Field(ctx context.Context, point Point, t time.Time) (IGRFresults, error) {
+	if err := ctx.Err(); err != nil {
+		return IGRFresults{}, err
+	}
+	return igd.IGRFAt(point.Lat, point.Lon, point.Alt, t)
+}
+
+// Name returns the name of the model in use, e.g. IGRF-14.
+func (igd *IGRFdata) Name() string {
+	if igd.shc == nil {
+		return ""
+	}
+	return igd.shc.Name()
+}
@@ -0,0 +1,46 @@
+package igrf
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIGRFdata_Field(t *testing.T) {
+	var model FieldModel = New()
+	if got := model.Name(); got != "IGRF-14" {
+		t.Errorf("Name() = %v, want %v", got, "IGRF-14")
+	}
+	// 2021-07-02 12:00 UTC is the middle of 2021
+	moment := time.Date(2021, time.July, 2, 12, 0, 0, 0, time.UTC)
+	got, err := model.Field(context.Background(), Point{Lat: 46.9, Lon: 39.9, Alt: 0.0}, moment)
+	if err != nil {
+		t.Fatalf("Field() error = %v", err)
+	}
+	want, _ := New().IGRF(46.9, 39.9, 0.0, 2021.5)
+	if got != want {
+		t.Errorf("Field() = %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := model.Field(ctx, Point{Lat: 46.9, Lon: 39.9}, moment); err == nil {
+		t.Errorf("Field() expected an error for the cancelled context")
+	}
+	if _, err := model.Field(context.Background(), Point{Lat: 46.9, Lon: 39.9}, time.Date(1899, time.December, 31, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("Field() expected an error for the date out of range")
+	}
+}
+
+func TestIGRFdata_IGRFAt(t *testing.T) {
+	igrf_data := New()
+	// the middle of the leap year 2020
+	got, err := igrf_data.IGRFAt(59.9, 39.9, 0.0, time.Date(2020, time.July, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("IGRFAt() error = %v", err)
+	}
+	want, _ := igrf_data.IGRF(59.9, 39.9, 0.0, 2020.5)
+	if got != want {
+		t.Errorf("IGRFAt() = %v, want %v", got, want)
+	}
+}
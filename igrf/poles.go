@@ -0,0 +1,132 @@
+package igrf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/proway2/go-igrf/calc"
+)
+
+const (
+	// the dip pole is found when the horizontal intensity is below this value, nT
+	dip_pole_tolerance      = 0.01
+	dip_pole_max_iterations = 100
+	// the maximal distance of a single step of the search, km
+	dip_pole_max_step = 500.0
+	// the reference radius of the Earth in IGRF, km
+	earths_radius = 6371.2
+)
+
+// DipPolesResults represents locations of dip poles and of the eccentric dipole centre.
+//
+// North, South - points where the horizontal intensity is zero at the given altitude.
+//
+// CentreX, CentreY, CentreZ - offset of the eccentric dipole from the Earth's centre in km along ECEF axes.
+type DipPolesResults struct {
+	North   Point
+	South   Point
+	CentreX float64
+	CentreY float64
+	CentreZ float64
+}
+
+// DipPoles finds the north and south dip (magnetic) poles, points where the field is vertical,
+// at the altitude `alt` (km above mean sea level, see `IGRF`) for the decimal `date`,
+// and computes the centre of the eccentric dipole from coeffs of degrees 1 and 2.
+//
+// The search starts at poles of the centred dipole and follows Newton steps on the horizontal components
+// using the spatial gradient of the field, see `Gradient`.
+func (igd *IGRFdata) DipPoles(date, alt float64) (DipPolesResults, error) {
+	if err := checkInitialConditions(0, 0, alt); err != nil {
+		return DipPolesResults{}, err
+	}
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return DipPolesResults{}, err
+	}
+	dp, err := igd.dipole(date)
+	if err != nil {
+		return DipPolesResults{}, err
+	}
+	pole_lat := 90 - rad2deg(dp.pole_colat)
+	pole_lon := rad2deg(dp.pole_lon)
+	north, err := findDipPole(set, pole_lat, pole_lon, alt)
+	if err != nil {
+		return DipPolesResults{}, fmt.Errorf("north dip pole: %w", err)
+	}
+	south, err := findDipPole(set, -pole_lat, normalizeLongitude(pole_lon+180), alt)
+	if err != nil {
+		return DipPolesResults{}, fmt.Errorf("south dip pole: %w", err)
+	}
+	results := DipPolesResults{North: north, South: south}
+	results.CentreX, results.CentreY, results.CentreZ, err = eccentricDipoleCentre(*set.main)
+	return results, err
+}
+
+// Searches for the point where the horizontal intensity is zero starting from `lat`, `lon`.
+func findDipPole(set coeffsSet, lat, lon, alt float64) (Point, error) {
+	for i := 0; i < dip_pole_max_iterations; i++ {
+		b, grad := calc.Gradient(lat, lon, alt, set.nmax, set.main)
+		if math.Hypot(b[0], b[1]) < dip_pole_tolerance {
+			return Point{Lat: lat, Lon: lon, Alt: alt}, nil
+		}
+		// solve the linearised horizontal components for the step north and east in km
+		det := grad[0][0]*grad[1][1] - grad[0][1]*grad[1][0]
+		if det == 0 {
+			return Point{}, errors.New("the horizontal field gradient is degenerate")
+		}
+		step_north := -(b[0]*grad[1][1] - b[1]*grad[0][1]) / det
+		step_east := -(grad[0][0]*b[1] - grad[1][0]*b[0]) / det
+		distance := math.Hypot(step_north, step_east)
+		if distance > dip_pole_max_step {
+			distance = dip_pole_max_step
+		}
+		lat, lon = destination(lat, lon, math.Atan2(step_east, step_north), distance/(earths_radius+alt))
+	}
+	return Point{}, fmt.Errorf("no convergence after %v iterations", dip_pole_max_iterations)
+}
+
+// Returns the point (decimal degrees) at the angular `distance` (radians) from `lat`, `lon` along the great circle
+// with the initial `bearing` (radians, clockwise from north).
+func destination(lat, lon, bearing, distance float64) (float64, float64) {
+	slat, clat := math.Sincos(lat * math.Pi / 180)
+	sd, cd := math.Sincos(distance)
+	sb, cb := math.Sincos(bearing)
+	slat2 := slat*cd + clat*sd*cb
+	lat2 := math.Asin(math.Max(-1, math.Min(1, slat2)))
+	dlon := math.Atan2(sb*sd*clat, cd-slat*slat2)
+	return rad2deg(lat2), normalizeLongitude(lon + rad2deg(dlon))
+}
+
+// Returns the longitude (decimal degrees) in the range [-180, 180).
+func normalizeLongitude(lon float64) float64 {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
+
+// Computes the offset of the eccentric dipole centre in km along ECEF axes from coeffs of degrees 1 and 2,
+// see Fraser-Smith (1987) Centered and eccentric geomagnetic dipoles and their poles, 1600-1985.
+func eccentricDipoleCentre(gh []float64) (float64, float64, float64, error) {
+	if len(gh) < 8 {
+		return 0, 0, 0, errors.New("coeffs of degree 2 are missing")
+	}
+	g10, g11, h11 := gh[0], gh[1], gh[2]
+	g20, g21, h21, g22, h22 := gh[3], gh[4], gh[5], gh[6], gh[7]
+	b0_2 := g10*g10 + g11*g11 + h11*h11
+	if b0_2 == 0 {
+		return 0, 0, 0, errors.New("dipole coeffs are zero")
+	}
+	sqrt3 := math.Sqrt(3)
+	l0 := 2*g10*g20 + sqrt3*(g11*g21+h11*h21)
+	l1 := -g11*g20 + sqrt3*(g10*g21+g11*g22+h11*h22)
+	l2 := -h11*g20 + sqrt3*(g10*h21-h11*g22+g11*h22)
+	e := (l0*g10 + l1*g11 + l2*h11) / (4 * b0_2)
+	x := earths_radius * (l1 - g11*e) / (3 * b0_2)
+	y := earths_radius * (l2 - h11*e) / (3 * b0_2)
+	z := earths_radius * (l0 - g10*e) / (3 * b0_2)
+	return x, y, z, nil
+}
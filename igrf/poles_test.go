@@ -0,0 +1,72 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_DipPoles(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name      string
+		date      float64
+		alt       float64
+		wantNorth Point
+		wantSouth Point
+		wantErr   bool
+	}{
+		// positions of dip poles at the sea level for IGRF-14
+		{name: "2020", date: 2020.0, wantNorth: Point{86.49, 162.91, 0}, wantSouth: Point{-64.08, 135.87, 0}},
+		{name: "1900", date: 1900.0, wantNorth: Point{70.46, -96.19, 0}, wantSouth: Point{-71.72, 148.32, 0}},
+		{name: "Altitude out of range", date: 2020.0, alt: 1000.0, wantErr: true},
+		{name: "Date out of range", date: 1800.0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.DipPoles(tt.date, tt.alt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DipPoles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			for _, pole := range []struct{ got, want Point }{{got.North, tt.wantNorth}, {got.South, tt.wantSouth}} {
+				if math.Abs(pole.got.Lat-pole.want.Lat) > 0.01 || math.Abs(pole.got.Lon-pole.want.Lon) > 0.01 {
+					t.Errorf("DipPoles() pole = %v, want %v", pole.got, pole.want)
+				}
+				res, _ := igrf_data.IGRF(pole.got.Lat, pole.got.Lon, pole.got.Alt, tt.date)
+				if res.HorizontalIntensity > dip_pole_tolerance {
+					t.Errorf("DipPoles() H at the pole = %v, want 0", res.HorizontalIntensity)
+				}
+			}
+		})
+	}
+}
+
+func TestIGRFdata_DipPolesEccentricDipole(t *testing.T) {
+	igrf_data := New()
+	got, err := igrf_data.DipPoles(2020.0, 0.0)
+	if err != nil {
+		t.Fatalf("DipPoles() error = %v", err)
+	}
+	// the centre is shifted by about 590 km towards the western Pacific
+	distance := math.Sqrt(got.CentreX*got.CentreX + got.CentreY*got.CentreY + got.CentreZ*got.CentreZ)
+	lat := rad2deg(math.Asin(got.CentreZ / distance))
+	lon := rad2deg(math.Atan2(got.CentreY, got.CentreX))
+	if math.Abs(distance-590.5) > 1 || math.Abs(lat-22.7) > 0.1 || math.Abs(lon-137.0) > 0.1 {
+		t.Errorf("DipPoles() centre distance, lat, lon = %v, %v, %v, want 590.5, 22.7, 137.0", distance, lat, lon)
+	}
+}
+
+func TestDestination(t *testing.T) {
+	// crossing the North pole along a meridian
+	lat, lon := destination(89.0, 10.0, 0, 2*math.Pi/180)
+	if math.Abs(lat-89.0) > 1e-9 || math.Abs(lon+170.0) > 1e-9 {
+		t.Errorf("destination() = %v, %v, want 89, -170", lat, lon)
+	}
+	lat, lon = destination(0.0, 179.0, math.Pi/2, 2*math.Pi/180)
+	if math.Abs(lat) > 1e-9 || math.Abs(lon+179.0) > 1e-9 {
+		t.Errorf("destination() = %v, %v, want 0, -179", lat, lon)
+	}
+}
@@ -0,0 +1,30 @@
+package igrf
+
+import "github.com/proway2/go-igrf/calc"
+
+// PotentialResults is the magnetic scalar potential and the field computed as its negative gradient.
+type PotentialResults struct {
+	Potential         float64 // nT·km
+	NorthComponent    float64 // nT
+	EastComponent     float64 // nT
+	VerticalComponent float64 // nT
+}
+
+// Potential computes the magnetic scalar potential V of the main field for a given set of coordinates and date,
+// see `IGRF` for valid values. Components X, Y, Z are computed analytically as -grad(V) in the local geodetic frame.
+func (igd *IGRFdata) Potential(lat, lon, alt, date float64) (PotentialResults, error) {
+	if err := checkInitialConditions(lat, lon, alt); err != nil {
+		return PotentialResults{}, err
+	}
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return PotentialResults{}, err
+	}
+	v, x, y, z := calc.Potential(lat, lon, alt, set.nmax, set.main)
+	return PotentialResults{
+		Potential:         v,
+		NorthComponent:    x,
+		EastComponent:     y,
+		VerticalComponent: z,
+	}, nil
+}
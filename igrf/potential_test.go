@@ -0,0 +1,36 @@
+package igrf
+
+import "testing"
+
+func TestIGRFdata_Potential(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name    string
+		lat     float64
+		lon     float64
+		alt     float64
+		wantErr bool
+	}{
+		{name: "Mid latitude", lat: 59.9, lon: 39.9, alt: 10.0},
+		{name: "Southern hemisphere", lat: -45.5, lon: -120.3, alt: 300.0},
+		{name: "Latitude out of range", lat: 95.0, lon: 0.0, alt: 0.0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.Potential(tt.lat, tt.lon, tt.alt, 2021.5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Potential() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			want, _ := igrf_data.IGRF(tt.lat, tt.lon, tt.alt, 2021.5)
+			if !isClose(got.NorthComponent, want.NorthComponent, 1e-9, 1e-6) ||
+				!isClose(got.EastComponent, want.EastComponent, 1e-9, 1e-6) ||
+				!isClose(got.VerticalComponent, want.VerticalComponent, 1e-9, 1e-6) {
+				t.Errorf("Potential() = %v, want components of %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package igrf
+
+import (
+	"sync"
+	"time"
+
+	"github.com/proway2/go-igrf/calc"
+	"github.com/proway2/go-igrf/coeffs"
+)
+
+// Snapshot holds coeffs interpolated for a fixed date, so that the field could be computed
+// for many locations without interpolating coeffs again.
+//
+// A snapshot is immutable and safe for concurrent use.
+type Snapshot struct {
+	date       float64
+	coeffs     coeffsSet
+	workspaces *sync.Pool
+}
+
+// Snapshot returns coeffs interpolated for the decimal `date`.
+func (igd *IGRFdata) Snapshot(date float64) (*Snapshot, error) {
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{date: date, coeffs: set}
+	snapshot.workspaces = &sync.Pool{New: func() interface{} { return calc.NewWorkspace(set.nmax) }}
+	return snapshot, nil
+}
+
+// SnapshotAt returns coeffs interpolated for time `t`, see `IGRFAt`.
+func (igd *IGRFdata) SnapshotAt(t time.Time) (*Snapshot, error) {
+	return igd.Snapshot(coeffs.DecimalYear(t))
+}
+
+// Date returns the decimal date of the snapshot.
+func (s *Snapshot) Date() float64 {
+	return s.date
+}
+
+// Field computes values for the geomagnetic field and secular variation for a given set of coordinates
+// at the date of the snapshot, see `IGRF` for valid values.
+func (s *Snapshot) Field(lat, lon, alt float64) (IGRFresults, error) {
+	if err := checkInitialConditions(lat, lon, alt); err != nil {
+		return IGRFresults{}, err
+	}
+	ws := s.workspaces.Get().(*calc.Workspace)
+	defer s.workspaces.Put(ws)
+	return s.field(ws, lat, lon, alt), nil
+}
+
+// Computes values using the workspace `ws`, coordinates must be checked beforehand.
+func (s *Snapshot) field(ws *calc.Workspace, lat, lon, alt float64) IGRFresults {
+	ws.SetLatitude(lat, alt, s.coeffs.nmax)
+	ws.SetLongitude(lon, s.coeffs.nmax)
+	return s.coeffs.results(ws)
+}
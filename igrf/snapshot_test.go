@@ -0,0 +1,46 @@
+package igrf
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIGRFdata_Snapshot(t *testing.T) {
+	igrf_data := New()
+	snapshot, err := igrf_data.Snapshot(2021.5)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if snapshot.Date() != 2021.5 {
+		t.Errorf("Date() = %v, want %v", snapshot.Date(), 2021.5)
+	}
+	points := []Point{{59.9, 39.9, 0.0}, {-64.081, 135.866, 0.0}, {89.9999, -120.0, 100.0}, {0, 0, 600}}
+	var wg sync.WaitGroup
+	for _, point := range points {
+		wg.Add(1)
+		go func(point Point) {
+			defer wg.Done()
+			got, err := snapshot.Field(point.Lat, point.Lon, point.Alt)
+			if err != nil {
+				t.Errorf("Field() error = %v", err)
+				return
+			}
+			want, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, 2021.5)
+			if got != want {
+				t.Errorf("Field() = %v, want %v", got, want)
+			}
+		}(point)
+	}
+	wg.Wait()
+	if _, err := snapshot.Field(91.0, 0, 0); err == nil {
+		t.Errorf("Field() expected an error for latitude out of range")
+	}
+	if _, err := igrf_data.Snapshot(1800.0); err == nil {
+		t.Errorf("Snapshot() expected an error for date out of range")
+	}
+	at, err := igrf_data.SnapshotAt(time.Date(2021, time.July, 2, 12, 0, 0, 0, time.UTC))
+	if err != nil || at.Date() != 2021.5 {
+		t.Errorf("SnapshotAt() date = %v, error = %v, want %v", at.Date(), err, 2021.5)
+	}
+}
@@ -0,0 +1,104 @@
+package igrf
+
+import (
+	"errors"
+	"math"
+
+	"github.com/proway2/go-igrf/calc"
+)
+
+// SVMode defines how secular variation (SV) is computed, see `WithSVMode`.
+type SVMode int
+
+const (
+	// AnalyticSV is the time derivative of the field computed from the rate of change of coeffs
+	// between epochs bracketing the date. This is the default.
+	AnalyticSV SVMode = iota
+	// FiniteDifferenceSV is the difference between values one year after the date and values for the date,
+	// this is what the reference C and FORTRAN implementations do.
+	FiniteDifferenceSV
+)
+
+// WithSVMode returns a copy of the IGRF structure that computes SV in the given `mode`, coeffs are shared.
+func (igd *IGRFdata) WithSVMode(mode SVMode) *IGRFdata {
+	copied := *igd
+	copied.sv_mode = mode
+	return &copied
+}
+
+// SVMode returns the mode SV is computed in.
+func (igd *IGRFdata) SVMode() SVMode {
+	return igd.sv_mode
+}
+
+// coeffs for a single date: the main field coeffs and either their SV (AnalyticSV)
+// or coeffs one year later (FiniteDifferenceSV)
+type coeffsSet struct {
+	main, second *[]float64
+	nmax         int
+	sv_mode      SVMode
+}
+
+// Returns coeffs for the `date` according to the SV mode.
+func (igd *IGRFdata) coeffsSet(date float64) (coeffsSet, error) {
+	if igd.shc == nil {
+		return coeffsSet{}, errors.New("IGRFdata structure is not initialized")
+	}
+	set := coeffsSet{sv_mode: igd.sv_mode}
+	var err error
+	if igd.sv_mode == FiniteDifferenceSV {
+		set.main, set.second, set.nmax, err = igd.shc.Coeffs(date)
+	} else {
+		set.main, set.second, set.nmax, err = igd.shc.CoeffsSV(date)
+	}
+	return set, err
+}
+
+// Computes the field and its SV (x, y, z, xdot, ydot, zdot) at the location set in the workspace `ws`.
+func (set coeffsSet) sum(ws *calc.Workspace) (float64, float64, float64, float64, float64, float64) {
+	x, y, z, xtemp, ytemp, ztemp := ws.Sum(set.nmax, set.main, set.second)
+	if set.sv_mode == FiniteDifferenceSV {
+		return x, y, z, xtemp - x, ytemp - y, ztemp - z
+	}
+	return x, y, z, xtemp, ytemp, ztemp
+}
+
+// Computes `IGRFresults` at the location set in the workspace `ws`.
+func (set coeffsSet) results(ws *calc.Workspace) IGRFresults {
+	x, y, z, xtemp, ytemp, ztemp := ws.Sum(set.nmax, set.main, set.second)
+	if set.sv_mode == FiniteDifferenceSV {
+		return newResults(x, y, z, xtemp, ytemp, ztemp)
+	}
+	return newAnalyticResults(x, y, z, xtemp, ytemp, ztemp)
+}
+
+// Populates `IGRFresults` from X, Y, Z and their time derivatives, SV of D, I, H and F are derived analytically.
+func newAnalyticResults(x, y, z, xdot, ydot, zdot float64) IGRFresults {
+	d, i, h, f := calc.Dihf(x, y, z)
+	hdot := (x*xdot + y*ydot) / h
+	fdot := (x*xdot + y*ydot + z*zdot) / f
+	// radians per year into arcmin per year
+	ddot := rad2deg((x*ydot-y*xdot)/(h*h)) * 60
+	idot := rad2deg((h*zdot-z*hdot)/(f*f)) * 60
+	if h == 0 {
+		hdot = math.Sqrt(xdot*xdot + ydot*ydot)
+		ddot = math.NaN()
+		idot = math.NaN()
+	}
+	return IGRFresults{
+		Declination:         rad2deg(d),
+		DeclinationSV:       ddot,
+		Inclination:         rad2deg(i),
+		InclinationSV:       idot,
+		HorizontalIntensity: h,
+		HorizontalSV:        hdot,
+		NorthComponent:      x,
+		NorthSV:             xdot,
+		EastComponent:       y,
+		EastSV:              ydot,
+		VerticalComponent:   z,
+		VerticalSV:          zdot,
+		TotalIntensity:      f,
+		TotalSV:             fdot,
+	}
+}
@@ -0,0 +1,84 @@
+package igrf
+
+import (
+	"testing"
+)
+
+func TestAnalyticSV(t *testing.T) {
+	igrf_data := New()
+	if igrf_data.SVMode() != AnalyticSV {
+		t.Errorf("SVMode() = %v, want %v", igrf_data.SVMode(), AnalyticSV)
+	}
+	// SV is compared with the central difference of main field values
+	const step = 0.01
+	points := []Point{{59.9, 39.9, 0.0}, {-64.081, 135.866, 0.0}, {0.0, -120.0, 300.0}, {46.9, 39.9, 1.1}}
+	for _, date := range []float64{1955.3, 2021.5, 2027.3} {
+		for _, point := range points {
+			got, err := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, date)
+			if err != nil {
+				t.Fatalf("IGRF() error = %v", err)
+			}
+			before, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, date-step)
+			after, _ := igrf_data.IGRF(point.Lat, point.Lon, point.Alt, date+step)
+			rate := func(a, b float64) float64 { return (b - a) / (2 * step) }
+			checks := []struct {
+				name      string
+				got, want float64
+			}{
+				{"DeclinationSV", got.DeclinationSV, rate(before.Declination, after.Declination) * 60},
+				{"InclinationSV", got.InclinationSV, rate(before.Inclination, after.Inclination) * 60},
+				{"HorizontalSV", got.HorizontalSV, rate(before.HorizontalIntensity, after.HorizontalIntensity)},
+				{"NorthSV", got.NorthSV, rate(before.NorthComponent, after.NorthComponent)},
+				{"EastSV", got.EastSV, rate(before.EastComponent, after.EastComponent)},
+				{"VerticalSV", got.VerticalSV, rate(before.VerticalComponent, after.VerticalComponent)},
+				{"TotalSV", got.TotalSV, rate(before.TotalIntensity, after.TotalIntensity)},
+			}
+			for _, check := range checks {
+				// interpolation respects leap years, so the rate slightly differs within a year
+				if !isClose(check.got, check.want, 0.01, 0.05) {
+					t.Errorf("IGRF(%v, %v) %v = %v, want %v", point, date, check.name, check.got, check.want)
+				}
+			}
+		}
+	}
+}
+
+func TestFiniteDifferenceSV(t *testing.T) {
+	igrf_data := New().WithSVMode(FiniteDifferenceSV)
+	if igrf_data.SVMode() != FiniteDifferenceSV {
+		t.Errorf("SVMode() = %v, want %v", igrf_data.SVMode(), FiniteDifferenceSV)
+	}
+	// both dates are within the same interval between epochs
+	got, _ := igrf_data.IGRF(59.9, 39.9, 0.0, 2021.5)
+	later, _ := igrf_data.IGRF(59.9, 39.9, 0.0, 2022.5)
+	if !isClose(got.NorthSV, later.NorthComponent-got.NorthComponent, 1e-9, 1e-6) {
+		t.Errorf("IGRF() NorthSV = %v, want %v", got.NorthSV, later.NorthComponent-got.NorthComponent)
+	}
+	analytic, _ := New().IGRF(59.9, 39.9, 0.0, 2021.5)
+	finite, _ := igrf_data.IGRF(59.9, 39.9, 0.0, 2021.5)
+	if analytic.NorthComponent != finite.NorthComponent {
+		t.Errorf("IGRF() main field depends on SV mode: %v, %v", analytic.NorthComponent, finite.NorthComponent)
+	}
+}
+
+func TestFiniteDifferenceSVBaseline(t *testing.T) {
+	// values computed before SV modes were introduced, when SV was the yearly difference only
+	tests := []testsData{
+		{args: args{59.9, 39.9, 0, 2021.5}, want: IGRFresults{Declination: 14.360152984214686, DeclinationSV: 7.528861944056518, Inclination: 74.27175587473822, InclinationSV: 1.7899144479153344, HorizontalIntensity: 14612.041590467534, HorizontalSV: -9.981770937110923, NorthComponent: 14155.50122639348, NorthSV: -17.635213400288194, EastComponent: 3624.0232437341865, EastSV: 28.49582833057002, VerticalComponent: 51885.79649682732, VerticalSV: 68.212499369125, TotalIntensity: 53904.05956467223, TotalSV: 62.960009786453156}},
+		{args: args{-64.081, 135.866, 0, 2021.5}, want: IGRFresults{Declination: 120.79239019606321, DeclinationSV: -29.366750756763654, Inclination: -89.93613249107477, InclinationSV: 2.5341298941169836, HorizontalIntensity: 74.38420762828781, HorizontalSV: 49.166948926775646, NorthComponent: -38.0794164111295, NorthSV: -24.261036746088735, EastComponent: 63.898109442111284, EastSV: 42.772205951846736, VerticalComponent: -66730.32134858164, VerticalSV: 12.591833062819205, TotalIntensity: 66730.36280656142, TotalSV: -12.518892101652455}},
+		{args: args{0, -120, 300, 1955.25}, want: IGRFresults{Declination: 9.097847235340117, DeclinationSV: 0.20318369716746926, Inclination: 9.036332933222358, InclinationSV: -1.7257515844616285, HorizontalIntensity: 28359.124721739394, HorizontalSV: -35.27772090110739, NorthComponent: 28002.359800429134, NorthSV: -35.09866881633934, EastComponent: 4484.172230242807, EastSV: -3.9251697475529, VerticalComponent: 4510.080427268554, VerticalSV: -20.187423613794635, TotalIntensity: 28715.514629614445, TotalSV: -38.006925646095624}},
+		{args: args{-33.3, 170, 600, 2000}, want: IGRFresults{Declination: 16.328996317608198, DeclinationSV: -0.12958237299648998, Inclination: -60.368037737618856, InclinationSV: 0.20133953215434341, HorizontalIntensity: 19928.257797722676, HorizontalSV: -12.80851462091232, NorthComponent: 19124.414232104733, NorthSV: -12.080813750657398, EastComponent: 5602.87775445481, EastSV: -4.3215608041173255, VerticalComponent: -35034.55737507926, VerticalSV: 27.28866397021193, TotalIntensity: 40305.777120906816, TotalSV: -30.052636304513726}},
+		{args: args{45, 10, 10, 2026.7}, want: IGRFresults{Declination: 3.8017556911120107, DeclinationSV: 6.659211166970654, Inclination: 61.322804756166825, InclinationSV: 1.044546651927298, HorizontalIntensity: 22804.408714633137, HorizontalSV: 7.666302110192191, NorthComponent: 22754.226177025896, NorthSV: 4.67680381333048, EastComponent: 1512.0343609803426, EastSV: 44.59715555545904, VerticalComponent: 41692.491500155964, VerticalSV: 44.13256511187501, TotalIntensity: 47521.625648904446, TotalSV: 42.40019978732744}},
+		{args: args{89.5, -100, 100, 1912.3}, want: IGRFresults{Declination: -149.13788806720984, DeclinationSV: 7.328262577927447, Inclination: 86.46332723117848, InclinationSV: 0.8413108571835957, HorizontalIntensity: 3360.910879340685, HorizontalSV: -16.272020005501417, NorthComponent: -2885.0203786246966, NorthSV: 17.63188441945522, EastComponent: -1724.058976308754, EastSV: 2.2307843353630687, VerticalComponent: 54379.18329165271, VerticalSV: -47.32515427619364, TotalIntensity: 54482.94501406869, TotalSV: -48.237172844979796}},
+	}
+	igrf_data := New().WithSVMode(FiniteDifferenceSV)
+	for _, tt := range tests {
+		got, err := igrf_data.IGRF(tt.args.lat, tt.args.lon, tt.args.alt, tt.args.date)
+		if err != nil {
+			t.Fatalf("IGRF() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("IGRF(%v) = %#v, want %#v", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package igrf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/proway2/go-igrf/calc"
+)
+
+// defaults of `TraceOptions`
+const (
+	trace_default_max_length = 1000000.0
+	trace_default_step       = 10.0
+	trace_default_tolerance  = 1e-4
+	// the minimal step, km
+	trace_min_step = 1e-3
+	// the default maximal step relative to the distance from the Earth's centre
+	trace_max_step_ratio = 0.05
+	// the accuracy of footpoint altitudes, km
+	trace_altitude_tolerance = 1e-6
+)
+
+// TraceOptions controls field-line tracing, zero values are replaced by defaults.
+//
+// TargetAlt - geodetic altitude of footpoints in km, tracing stops when the line descends below it, 0 by default.
+//
+// MaxLength - the maximal length of the line in each direction from the start in km, 1000000 by default.
+//
+// Step - the initial integration step in km, 10 by default.
+//
+// Tolerance - the maximal position error of a single step in km, 1e-4 by default.
+type TraceOptions struct {
+	TargetAlt float64
+	MaxLength float64
+	Step      float64
+	Tolerance float64
+}
+
+// FieldLine represents a traced field line.
+//
+// Points - the polyline from the end traced against the field to the end traced along the field.
+//
+// North, South - footpoints at the target altitude reached along and against the field respectively,
+// for the IGRF field lines go into the Earth in the northern magnetic hemisphere.
+//
+// NorthFound, SouthFound - whether the footpoints were reached before the maximal length.
+type FieldLine struct {
+	Points     []Point
+	North      Point
+	South      Point
+	NorthFound bool
+	SouthFound bool
+}
+
+// TraceFieldLine integrates along the direction of the main field from the `start` point for the decimal `date`
+// in both directions until the line reaches the target altitude or the maximal length, see `TraceOptions`.
+//
+// The integration is done by the Runge-Kutta method of the 4th order with the step control in geocentric
+// Cartesian coordinates, so the line could go up to several Earth radii, beyond the altitude limit of `IGRF`.
+func (igd *IGRFdata) TraceFieldLine(start Point, date float64, opts TraceOptions) (FieldLine, error) {
+	if err := checkTraceConditions(start, opts); err != nil {
+		return FieldLine{}, err
+	}
+	tr, err := igd.newTracer(date, opts)
+	if err != nil {
+		return FieldLine{}, err
+	}
+	return tr.fieldLine(start), nil
+}
+
+// integrates along the field with the coeffs for a fixed date
+type tracer struct {
+	set  coeffsSet
+	ws   *calc.Workspace
+	opts TraceOptions
+	// the maximal step relative to the distance from the Earth's centre
+	max_step_ratio float64
+}
+
+// Returns a tracer for the `date` with defaults applied to `opts`.
+func (igd *IGRFdata) newTracer(date float64, opts TraceOptions) (*tracer, error) {
+	set, err := igd.coeffsSet(date)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxLength == 0 {
+		opts.MaxLength = trace_default_max_length
+	}
+	if opts.Step == 0 {
+		opts.Step = trace_default_step
+	}
+	if opts.Tolerance == 0 {
+		opts.Tolerance = trace_default_tolerance
+	}
+	return &tracer{set: set, ws: calc.NewWorkspace(set.nmax), opts: opts, max_step_ratio: trace_max_step_ratio}, nil
+}
+
+// Traces the field line through the `start` point in both directions.
+func (tr *tracer) fieldLine(start Point) FieldLine {
+	position := toECEF(start)
+	backward, south_found := tr.trace(position, -1)
+	forward, north_found := tr.trace(position, 1)
+	line := FieldLine{
+		Points:     make([]Point, 0, len(backward)+len(forward)-1),
+		NorthFound: north_found,
+		SouthFound: south_found,
+	}
+	for i := len(backward) - 1; i > 0; i-- {
+		line.Points = append(line.Points, backward[i])
+	}
+	line.Points = append(line.Points, forward...)
+	if south_found {
+		line.South = line.Points[0]
+	}
+	if north_found {
+		line.North = line.Points[len(line.Points)-1]
+	}
+	return line
+}
+
+// Returns the main field in nT along ECEF axes at the geocentric Cartesian `position` in km.
+func (tr *tracer) field(position [3]float64) [3]float64 {
+	radius := math.Sqrt(position[0]*position[0] + position[1]*position[1] + position[2]*position[2])
+	lat := rad2deg(math.Asin(position[2] / radius))
+	lon := rad2deg(math.Atan2(position[1], position[0]))
+	tr.ws.SetGeocentric(lat, radius, tr.set.nmax)
+	tr.ws.SetLongitude(lon, tr.set.nmax)
+	north, east, down, _, _, _ := tr.ws.Sum(tr.set.nmax, tr.set.main, tr.set.second)
+	x, y, z := NEDToECEF(lat, lon, north, east, down)
+	return [3]float64{x, y, z}
+}
+
+// Returns whether the field goes down at the geodetic `point`, i.e. the point is in the northern magnetic hemisphere.
+func (tr *tracer) northern(point Point) bool {
+	nmax := tr.set.nmax
+	tr.ws.SetLatitude(point.Lat, point.Alt, nmax)
+	tr.ws.SetLongitude(point.Lon, nmax)
+	_, _, z, _, _, _ := tr.ws.Sum(nmax, tr.set.main, tr.set.second)
+	return z > 0
+}
+
+// Returns the unit vector along the field, or against it for negative `sign`.
+func (tr *tracer) direction(position [3]float64, sign float64) [3]float64 {
+	b := tr.field(position)
+	magnitude := math.Sqrt(b[0]*b[0] + b[1]*b[1] + b[2]*b[2])
+	if magnitude == 0 {
+		return [3]float64{}
+	}
+	return [3]float64{sign * b[0] / magnitude, sign * b[1] / magnitude, sign * b[2] / magnitude}
+}
+
+// A single step of the length `h` (km) from `position` by the Runge-Kutta method of the 4th order.
+func (tr *tracer) rk4(position [3]float64, h, sign float64) [3]float64 {
+	shift := func(p, k [3]float64, scale float64) [3]float64 {
+		return [3]float64{p[0] + scale*k[0], p[1] + scale*k[1], p[2] + scale*k[2]}
+	}
+	k1 := tr.direction(position, sign)
+	k2 := tr.direction(shift(position, k1, h/2), sign)
+	k3 := tr.direction(shift(position, k2, h/2), sign)
+	k4 := tr.direction(shift(position, k3, h), sign)
+	var next [3]float64
+	for i := range next {
+		next[i] = position[i] + h/6*(k1[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+	return next
+}
+
+// Traces from the `position` in the direction defined by `sign`, returns points of the line starting
+// at the `position` and whether the target altitude was reached, then the last point is at the target altitude.
+func (tr *tracer) trace(position [3]float64, sign float64) ([]Point, bool) {
+	points := []Point{toGeodetic(position)}
+	h := tr.opts.Step
+	var length float64
+	for length < tr.opts.MaxLength {
+		radius := math.Sqrt(position[0]*position[0] + position[1]*position[1] + position[2]*position[2])
+		if max_step := tr.max_step_ratio * radius; h > max_step {
+			h = max_step
+		}
+		if remaining := tr.opts.MaxLength - length; h > remaining {
+			h = remaining
+		}
+		// the step doubling estimates the error
+		full := tr.rk4(position, h, sign)
+		next := tr.rk4(tr.rk4(position, h/2, sign), h/2, sign)
+		step_error := distance(full, next)
+		if step_error > tr.opts.Tolerance && h > trace_min_step {
+			h /= 2
+			continue
+		}
+		point := toGeodetic(next)
+		if point.Alt < tr.opts.TargetAlt {
+			return append(points, tr.footpoint(position, h, sign)), true
+		}
+		points = append(points, point)
+		position = next
+		length += h
+		if step_error < tr.opts.Tolerance/32 {
+			h *= 2
+		}
+	}
+	return points, false
+}
+
+// Finds the point at the target altitude within the step of the length `h` from the `position` by bisection.
+func (tr *tracer) footpoint(position [3]float64, h, sign float64) Point {
+	low, high := 0.0, h
+	point := toGeodetic(position)
+	for high-low > trace_min_step*1e-3 && math.Abs(point.Alt-tr.opts.TargetAlt) > trace_altitude_tolerance {
+		middle := (low + high) / 2
+		point = toGeodetic(tr.rk4(position, middle, sign))
+		if point.Alt < tr.opts.TargetAlt {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+	return point
+}
+
+// Returns the highest point of the line `points` ordered along the field, it's refined by the golden-section search
+// between neighbours of the highest vertex.
+func (tr *tracer) apex(points []Point) Point {
+	highest := 0
+	for i, point := range points {
+		if point.Alt > points[highest].Alt {
+			highest = i
+		}
+	}
+	first, last := highest, highest
+	if first > 0 {
+		first--
+	}
+	if last < len(points)-1 {
+		last++
+	}
+	if first == last {
+		return points[highest]
+	}
+	origin := toECEF(points[first])
+	var length float64
+	for i := first; i < last; i++ {
+		length += distance(toECEF(points[i]), toECEF(points[i+1]))
+	}
+	altitude := func(s float64) float64 {
+		return toGeodetic(tr.advance(origin, s, 1)).Alt
+	}
+	ratio := (math.Sqrt(5) - 1) / 2
+	low, high := 0.0, length
+	a, b := high-ratio*(high-low), low+ratio*(high-low)
+	alt_a, alt_b := altitude(a), altitude(b)
+	for high-low > trace_min_step*1e-3 {
+		if alt_a < alt_b {
+			low, a, alt_a = a, b, alt_b
+			b = low + ratio*(high-low)
+			alt_b = altitude(b)
+		} else {
+			high, b, alt_b = b, a, alt_a
+			a = high - ratio*(high-low)
+			alt_a = altitude(a)
+		}
+	}
+	return toGeodetic(tr.advance(origin, (low+high)/2, 1))
+}
+
+// Reverses the order of `points` in place.
+func reversePoints(points []Point) {
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+}
+
+// Moves from the `position` by the distance `s` (km) along the field, or against it for negative `sign`,
+// in equal steps not longer than the maximal step.
+func (tr *tracer) advance(position [3]float64, s, sign float64) [3]float64 {
+	steps := int(math.Ceil(s / (tr.max_step_ratio * magnitude(position))))
+	if steps < 1 {
+		steps = 1
+	}
+	for i := 0; i < steps; i++ {
+		position = tr.rk4(position, s/float64(steps), sign)
+	}
+	return position
+}
+
+// Converts the geocentric Cartesian `position` in km into a geodetic point.
+func toGeodetic(position [3]float64) Point {
+	lat, lon, alt := ECEFToGeodetic(position[0]*1000.0, position[1]*1000.0, position[2]*1000.0)
+	return Point{Lat: lat, Lon: lon, Alt: alt}
+}
+
+// Converts the geodetic `point` into geocentric Cartesian coordinates in km.
+func toECEF(point Point) [3]float64 {
+	x, y, z := GeodeticToECEF(point.Lat, point.Lon, point.Alt)
+	return [3]float64{x / 1000.0, y / 1000.0, z / 1000.0}
+}
+
+// Returns the distance between points `a` and `b`.
+func distance(a, b [3]float64) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+func checkTraceConditions(start Point, opts TraceOptions) error {
+	if err := checkInitialConditions(start.Lat, start.Lon, 0); err != nil {
+		return err
+	}
+	if start.Alt < -1.0 || opts.TargetAlt < -1.0 {
+		return errors.New("altitude must not be less than -1.0 km")
+	}
+	if start.Alt < opts.TargetAlt {
+		return fmt.Errorf("start altitude %v km is below the target altitude %v km", start.Alt, opts.TargetAlt)
+	}
+	if opts.MaxLength < 0 || opts.Step < 0 || opts.Tolerance < 0 {
+		return errors.New("trace options must not be negative")
+	}
+	return nil
+}
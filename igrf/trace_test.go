@@ -0,0 +1,83 @@
+package igrf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIGRFdata_TraceFieldLine(t *testing.T) {
+	igrf_data := New()
+	tests := []struct {
+		name      string
+		start     Point
+		opts      TraceOptions
+		wantNorth bool
+		wantSouth bool
+		wantErr   bool
+	}{
+		{name: "From the ground", start: Point{60, 30, 0}, wantNorth: true, wantSouth: true},
+		{name: "Above the equator", start: Point{0, 100, 3000}, wantNorth: true, wantSouth: true},
+		{name: "Southern hemisphere", start: Point{-30, 20, 500}, opts: TraceOptions{TargetAlt: 100}, wantNorth: true, wantSouth: true},
+		{name: "High latitude", start: Point{75, 20, 0}, wantNorth: true, wantSouth: true},
+		{name: "Short line", start: Point{60, 30, 0}, opts: TraceOptions{MaxLength: 1000}, wantNorth: true},
+		{name: "Below the target", start: Point{60, 30, 0}, opts: TraceOptions{TargetAlt: 100}, wantErr: true},
+		{name: "Negative step", start: Point{60, 30, 0}, opts: TraceOptions{Step: -1}, wantErr: true},
+		{name: "Latitude out of range", start: Point{95, 30, 0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := igrf_data.TraceFieldLine(tt.start, 2020.0, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TraceFieldLine() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.NorthFound != tt.wantNorth || got.SouthFound != tt.wantSouth {
+				t.Fatalf("TraceFieldLine() found north, south = %v, %v, want %v, %v", got.NorthFound, got.SouthFound, tt.wantNorth, tt.wantSouth)
+			}
+			for _, foot := range []struct {
+				found bool
+				point Point
+			}{{got.NorthFound, got.North}, {got.SouthFound, got.South}} {
+				if foot.found && math.Abs(foot.point.Alt-tt.opts.TargetAlt) > 1e-5 {
+					t.Errorf("TraceFieldLine() footpoint %v, want altitude %v", foot.point, tt.opts.TargetAlt)
+				}
+			}
+			// every segment of the line goes along the field
+			tr, _ := igrf_data.newTracer(2020.0, tt.opts)
+			for i := 1; i < len(got.Points); i++ {
+				a := toECEF(got.Points[i-1])
+				b := toECEF(got.Points[i])
+				length := distance(a, b)
+				if length < 1 {
+					continue
+				}
+				middle := [3]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2, (a[2] + b[2]) / 2}
+				direction := tr.direction(middle, 1)
+				cosine := ((b[0]-a[0])*direction[0] + (b[1]-a[1])*direction[1] + (b[2]-a[2])*direction[2]) / length
+				if cosine < 0.99 {
+					t.Errorf("TraceFieldLine() segment %v - %v deviates from the field, cos = %v", got.Points[i-1], got.Points[i], cosine)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestIGRFdata_TraceFieldLineReverse(t *testing.T) {
+	// tracing from a footpoint brings to the other footpoint
+	igrf_data := New()
+	line, err := igrf_data.TraceFieldLine(Point{60, 30, 0}, 2020.0, TraceOptions{})
+	if err != nil {
+		t.Fatalf("TraceFieldLine() error = %v", err)
+	}
+	reverse, err := igrf_data.TraceFieldLine(line.South, 2020.0, TraceOptions{})
+	if err != nil {
+		t.Fatalf("TraceFieldLine() error = %v", err)
+	}
+	if math.Abs(reverse.North.Lat-60) > 1e-3 || math.Abs(reverse.North.Lon-30) > 1e-3 {
+		t.Errorf("TraceFieldLine() north footpoint = %v, want %v", reverse.North, Point{60, 30, 0})
+	}
+}